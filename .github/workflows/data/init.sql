@@ -103,3 +103,19 @@ INSERT INTO product (name, attributes, metadata) VALUES
      '{"color": "blue", "size": 25, "details": {"manufacturer": "TechCo", "model": "Pro-300"}}',
      '{"tags": ["new", "featured"], "price": 199.99, "rating": 4.5}');
 
+-- Tickets table with enum columns for testing enum and enum list filtering
+CREATE SCHEMA app;
+CREATE TYPE app.ticket_status AS ENUM ('OPEN', 'CLOSED');
+CREATE TYPE app.ticket_label AS ENUM ('BUG', 'FEATURE');
+CREATE TABLE app.tickets (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    status app.ticket_status,
+    labels app.ticket_label[]
+);
+
+INSERT INTO app.tickets (name, status, labels) VALUES
+    ('crash', 'OPEN', '{BUG}'),
+    ('dark mode', 'OPEN', '{FEATURE}'),
+    ('triage', 'CLOSED', '{BUG,FEATURE}'),
+    ('question', 'CLOSED', '{}');
@@ -74,6 +74,9 @@ export default defineConfig({
             }, {
                 label: 'Delete',
                 link: '/mutations/delete'
+            }, {
+                label: 'SQL Functions',
+                link: '/mutations/functions'
             }]
         }, {
             label: 'Schema',
@@ -83,6 +86,16 @@ export default defineConfig({
                     link: '/schema/interfaces',
                     badge: {text: 'Experimental', variant: 'caution'},
                 },
+                {
+                    label: 'Unions',
+                    link: '/schema/unions',
+                    badge: {text: 'Experimental', variant: 'caution'},
+                },
+                {
+                    label: 'Federation',
+                    link: '/schema/federation',
+                    badge: {text: 'Experimental', variant: 'caution'},
+                },
                 {
                     label: 'Custom Operators',
                     link: '/schema/operators',
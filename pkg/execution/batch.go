@@ -0,0 +1,196 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type batchKey struct{}
+
+// BatchExtension is a gqlgen handler extension that plans the queries of the root fields of each query operation, so
+// executors can send the queries of sibling root fields in a single batch, see Batch. It should be added to the server
+// using handler.Server.Use.
+type BatchExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.RootFieldInterceptor
+} = BatchExtension{}
+
+func (BatchExtension) ExtensionName() string {
+	return "FastgqlBatch"
+}
+
+func (BatchExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation adds the operation's batch to query operations, the root fields of mutations are resolved in
+// order and are never batched
+func (BatchExtension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	opCtx := graphql.GetOperationContext(ctx)
+	if opCtx.Operation == nil || opCtx.Operation.Operation != ast.Query {
+		return next(ctx)
+	}
+	// __typename is resolved without a root resolver, root types not named Query only undercount the fields so
+	// their queries are dispatched earlier
+	var running int
+	for _, f := range graphql.CollectFields(opCtx, opCtx.Operation.SelectionSet, []string{"Query"}) {
+		if f.Name != "__typename" {
+			running++
+		}
+	}
+	return next(context.WithValue(ctx, batchKey{}, &operationBatch{ctx: ctx, running: running, pending: make(map[string]pendingBatch)}))
+}
+
+// InterceptRootField marks the root field as completed once it's resolved, so the queries of its siblings no longer
+// wait for it
+func (BatchExtension) InterceptRootField(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+	if b, ok := ctx.Value(batchKey{}).(*operationBatch); ok {
+		defer b.done()
+	}
+	return next(ctx)
+}
+
+// pendingBatch is the keys of a batch waiting to be dispatched
+type pendingBatch interface {
+	// len returns the number of keys
+	len() int
+	// dispatch fetches the keys and delivers their results
+	dispatch(ctx context.Context)
+}
+
+type batchCall[K comparable, V any] struct {
+	key    K
+	value  V
+	err    error
+	closed chan struct{}
+}
+
+type typedBatch[K comparable, V any] struct {
+	fetch BatchFunc[K, V]
+	calls []*batchCall[K, V]
+}
+
+func (t *typedBatch[K, V]) len() int {
+	return len(t.calls)
+}
+
+func (t *typedBatch[K, V]) dispatch(ctx context.Context) {
+	keys := make([]K, len(t.calls))
+	for i, c := range t.calls {
+		keys[i] = c.key
+	}
+	values, err := t.fetch(ctx, keys)
+	if err == nil && len(values) != len(keys) {
+		err = fmt.Errorf("batch function returned %d values for %d keys", len(values), len(keys))
+	}
+	for i, c := range t.calls {
+		if err != nil {
+			c.err = err
+		} else {
+			c.value = values[i]
+		}
+		close(c.closed)
+	}
+}
+
+// operationBatch holds the pending batches of a query operation. Batches are dispatched once every root field that
+// isn't resolved yet is waiting for a batch, so each dispatch holds the queries of all the sibling root fields.
+type operationBatch struct {
+	// ctx is the operation's context, batches are shared by root fields so they're never bound to a field's context
+	ctx     context.Context
+	mu      sync.Mutex
+	running int
+	pending map[string]pendingBatch
+}
+
+// add adds the call to the pending batch registered under name, dispatching the pending batches if no other root field
+// is running
+func add[K comparable, V any](b *operationBatch, name string, fetch BatchFunc[K, V], c *batchCall[K, V]) error {
+	b.mu.Lock()
+	p, ok := b.pending[name]
+	if !ok {
+		p = &typedBatch[K, V]{fetch: fetch}
+		b.pending[name] = p
+	}
+	tb, ok := p.(*typedBatch[K, V])
+	if !ok {
+		b.mu.Unlock()
+		return fmt.Errorf("batch %s was registered with a different type %T", name, p)
+	}
+	tb.calls = append(tb.calls, c)
+	b.running--
+	b.dispatchLocked()
+	return nil
+}
+
+// done marks a root field as resolved
+func (b *operationBatch) done() {
+	b.mu.Lock()
+	b.running--
+	b.dispatchLocked()
+}
+
+// dispatchLocked dispatches the pending batches if no root field is running and unlocks the batch. The root fields
+// waiting for the dispatched batches are counted as running before they're dispatched, so the queries they send once
+// their batch is resolved wait for the root fields of the batches still in flight.
+func (b *operationBatch) dispatchLocked() {
+	if b.running > 0 || len(b.pending) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	pending := b.pending
+	b.pending = make(map[string]pendingBatch)
+	for _, p := range pending {
+		b.running += p.len()
+	}
+	b.mu.Unlock()
+
+	for _, p := range pending {
+		go p.dispatch(b.ctx)
+	}
+}
+
+// Batch resolves the key with the other keys of the operation's batch registered under name. Batches are dispatched
+// to fetch once every root field of the query operation is either resolved or waiting for a batch, with the
+// operation's context. Keys of fields that aren't root fields, or of operations without a batch (see BatchExtension),
+// are fetched on their own.
+func Batch[K comparable, V any](ctx context.Context, name string, key K, fetch BatchFunc[K, V]) (V, error) {
+	b, ok := ctx.Value(batchKey{}).(*operationBatch)
+	if !ok || !isRootField(ctx) {
+		var zero V
+		values, err := fetch(ctx, []K{key})
+		if err != nil {
+			return zero, err
+		}
+		if len(values) != 1 {
+			return zero, fmt.Errorf("batch function returned %d values for 1 key", len(values))
+		}
+		return values[0], nil
+	}
+	c := &batchCall[K, V]{key: key, closed: make(chan struct{})}
+	if err := add(b, name, fetch, c); err != nil {
+		var zero V
+		return zero, err
+	}
+	<-c.closed
+	return c.value, c.err
+}
+
+// HasBatch returns true if the context's operation has a batch, see BatchExtension
+func HasBatch(ctx context.Context) bool {
+	_, ok := ctx.Value(batchKey{}).(*operationBatch)
+	return ok
+}
+
+// isRootField returns true if the context is of a root field's resolver, rather than of one of its children
+func isRootField(ctx context.Context) bool {
+	fc, rfc := graphql.GetFieldContext(ctx), graphql.GetRootFieldContext(ctx)
+	return fc != nil && rfc != nil && fc.Field.Field != nil && fc.Field.Field == rfc.Field.Field
+}
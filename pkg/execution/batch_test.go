@@ -0,0 +1,169 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+type operationKey struct{}
+
+// runOperation resolves the root fields of the query concurrently through BatchExtension, the same way gqlgen
+// resolves the root fields of a query operation, resolvers are called with their field's context
+func runOperation(t *testing.T, query string, resolvers map[string]func(ctx context.Context)) {
+	t.Helper()
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	require.NoError(t, err)
+	opCtx := &graphql.OperationContext{Doc: doc, Operation: doc.Operations[0]}
+	ctx := context.WithValue(graphql.WithOperationContext(context.Background(), opCtx), operationKey{}, true)
+
+	ext := BatchExtension{}
+	ext.InterceptOperation(ctx, func(ctx context.Context) graphql.ResponseHandler {
+		var wg sync.WaitGroup
+		for _, f := range graphql.CollectFields(opCtx, opCtx.Operation.SelectionSet, []string{"Query"}) {
+			if f.Name == "__typename" {
+				// __typename is resolved without a root resolver
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rctx := graphql.WithRootFieldContext(ctx, &graphql.RootFieldContext{Object: "Query", Field: f})
+				ext.InterceptRootField(rctx, func(ctx context.Context) graphql.Marshaler {
+					fctx := graphql.WithFieldContext(ctx, &graphql.FieldContext{Object: "Query", Field: f, IsResolver: true})
+					resolvers[f.Alias](fctx)
+					return graphql.Null
+				})
+			}()
+		}
+		wg.Wait()
+		return graphql.OneShot(&graphql.Response{})
+	})
+}
+
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *batchRecorder) fetch(ctx context.Context, keys []int) ([]int, error) {
+	r.mu.Lock()
+	r.batches = append(r.batches, keys)
+	r.mu.Unlock()
+	if ctx.Value(operationKey{}) == nil || graphql.GetRootFieldContext(ctx) != nil || ctx.Err() != nil {
+		return nil, errors.New("batch isn't fetched with the operation's context")
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	values := make([]int, len(keys))
+	for i, k := range keys {
+		values[i] = k * 10
+	}
+	return values, nil
+}
+
+func TestBatch(t *testing.T) {
+	t.Run("sibling_root_fields", func(t *testing.T) {
+		r := &batchRecorder{}
+		var a, b int
+		runOperation(t, `{ a b c __typename }`, map[string]func(ctx context.Context){
+			"a": func(ctx context.Context) {
+				var err error
+				a, err = Batch(ctx, "test", 1, r.fetch)
+				assert.NoError(t, err)
+			},
+			"b": func(ctx context.Context) {
+				var err error
+				b, err = Batch(ctx, "test", 2, r.fetch)
+				assert.NoError(t, err)
+			},
+			"c": func(ctx context.Context) {},
+		})
+		require.Len(t, r.batches, 1)
+		assert.ElementsMatch(t, []int{1, 2}, r.batches[0])
+		assert.Equal(t, 10, a)
+		assert.Equal(t, 20, b)
+	})
+	t.Run("sequential_batches", func(t *testing.T) {
+		r := &batchRecorder{}
+		runOperation(t, `{ a b }`, map[string]func(ctx context.Context){
+			"a": func(ctx context.Context) {
+				_, err := Batch(ctx, "test", 1, r.fetch)
+				assert.NoError(t, err)
+				v, err := Batch(ctx, "test", 3, r.fetch)
+				assert.NoError(t, err)
+				assert.Equal(t, 30, v)
+			},
+			"b": func(ctx context.Context) {
+				_, err := Batch(ctx, "test", 2, r.fetch)
+				assert.NoError(t, err)
+			},
+		})
+		require.Len(t, r.batches, 2)
+		assert.ElementsMatch(t, []int{1, 2}, r.batches[0])
+		assert.Equal(t, []int{3}, r.batches[1])
+	})
+	t.Run("field_cancellation", func(t *testing.T) {
+		r := &batchRecorder{}
+		runOperation(t, `{ a b }`, map[string]func(ctx context.Context){
+			"a": func(ctx context.Context) {
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				_, err := Batch(ctx, "test", 1, r.fetch)
+				assert.NoError(t, err, "a field's cancellation doesn't apply to the batch")
+			},
+			"b": func(ctx context.Context) {
+				_, err := Batch(ctx, "test", 2, r.fetch)
+				assert.NoError(t, err)
+			},
+		})
+		require.Len(t, r.batches, 1)
+	})
+	t.Run("fetch_error", func(t *testing.T) {
+		r := &batchRecorder{err: errors.New("failed")}
+		runOperation(t, `{ a b }`, map[string]func(ctx context.Context){
+			"a": func(ctx context.Context) {
+				_, err := Batch(ctx, "test", 1, r.fetch)
+				assert.EqualError(t, err, "failed")
+			},
+			"b": func(ctx context.Context) {
+				_, err := Batch(ctx, "test", 2, r.fetch)
+				assert.EqualError(t, err, "failed")
+			},
+		})
+		require.Len(t, r.batches, 1)
+	})
+	t.Run("nested_field", func(t *testing.T) {
+		var keys [][]int
+		runOperation(t, `{ a }`, map[string]func(ctx context.Context){
+			"a": func(ctx context.Context) {
+				child := graphql.WithFieldContext(ctx, &graphql.FieldContext{Object: "A", Field: graphql.CollectedField{Field: &ast.Field{Name: "b"}}})
+				v, err := Batch(child, "test", 1, func(ctx context.Context, k []int) ([]int, error) {
+					keys = append(keys, k)
+					return []int{10}, nil
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, 10, v)
+			},
+		})
+		assert.Equal(t, [][]int{{1}}, keys, "keys of nested fields are fetched on their own")
+	})
+	t.Run("no_batch", func(t *testing.T) {
+		ctx := context.Background()
+		assert.False(t, HasBatch(ctx))
+		v, err := Batch(ctx, "test", 1, func(ctx context.Context, keys []int) ([]int, error) {
+			return []int{10}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 10, v)
+	})
+}
@@ -1,9 +1,13 @@
 package builders
 
 import (
+	"time"
+
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/roneli/fastgql/pkg/log"
 )
@@ -11,6 +15,8 @@ import (
 const (
 	InputFieldName = "inputs"
 
+	defaultDialect = "postgres"
+
 	OrderingTypesAsc      OrderingTypes = "ASC"
 	OrderingTypesDesc     OrderingTypes = "DESC"
 	OrderingTypesAscNull  OrderingTypes = "ASC_NULL_FIRST"
@@ -24,6 +30,9 @@ type (
 		Logger log.Logger
 		// CustomOperators are user defined operators, can also be used to override existing default operators.
 		CustomOperators map[string]Operator
+		// CustomAggregates are user defined aggregate functions, can also be used to override the default max/min/avg/sum
+		// aggregates. Each must also be added to the schema using the schema.WithAggregates generator option.
+		CustomAggregates map[string]AggregateFunc
 
 		// TableNameGenerator allows defining how aliases "Table" names are generated in the query, this is mostly used for test
 		TableNameGenerator TableNameGenerator
@@ -35,6 +44,26 @@ type (
 		// Dialect specifies the SQL dialect to use (e.g., "postgres", "mysql", "snowflake").
 		// Defaults to "postgres" if not specified.
 		Dialect string
+
+		// TracerProvider enables OpenTelemetry spans for building and executing each root field.
+		// If nil, no spans are recorded.
+		TracerProvider trace.TracerProvider
+		// MeterProvider enables OpenTelemetry metrics (build time, database time and result size).
+		// If nil, no metrics are recorded.
+		MeterProvider metric.MeterProvider
+
+		// BatchQueries sends the read queries of sibling root fields of an operation to the database in a single
+		// batch instead of a round trip per field, identical queries are executed once. It requires the operation
+		// scoped batches of execution.BatchExtension.
+		BatchQueries bool
+
+		// StatementTimeout bounds how long each generated query may run, it can be overridden per type with
+		// @table(timeout:) and per request with sql.WithStatementTimeout. Zero means no timeout.
+		StatementTimeout time.Duration
+
+		// StreamChunkSize is the number of rows read at once from the cursor of a root list field with @stream, each
+		// chunk is sent to the client before the next one is read, see execution.StreamTransport. Defaults to 500.
+		StreamChunkSize int
 	}
 
 	OrderingTypes string
@@ -42,6 +71,10 @@ type (
 	OrderField struct {
 		Key  string
 		Type OrderingTypes
+		// Value is the argument of orderings by an expression, i.e. the point to order by distance from
+		Value any
+		// Path is the keys of the ordered field nested in the typed JSON field Key, i.e. [details price]
+		Path []string
 	}
 
 	// ColumnCaseConverter converts columns from ast.Field Name to database field name, by default it converts to snake case
@@ -56,6 +89,10 @@ type (
 	// AggregatorOperator gets called on aggregation methods // TBD //
 	AggregatorOperator func(table exp.AliasedExpression, fields []Field) (goqu.Expression, error)
 
+	// AggregateFunc aggregates a single column, i.e. stddev(col). Args are the arguments of the aggregate field,
+	// i.e. {p: 0.9} of percentile(p: 0.9), and are nil when the aggregate is used to filter or order groups.
+	AggregateFunc func(col exp.IdentifierExpression, args map[string]any) goqu.Expression
+
 	TableNameGenerator interface {
 		Generate(n int) string
 	}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"testing"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
@@ -102,6 +104,11 @@ func TestField_ForName(t *testing.T) {
 }
 
 func TestCollectOrdering(t *testing.T) {
+	orderingDef := &ast.Definition{Kind: ast.InputObject, Name: "PlaceOrdering", Fields: ast.FieldList{
+		{Name: "name", Type: ast.NamedType("_OrderingTypes", nil)},
+		{Name: "location", Type: ast.NamedType("_DistanceOrdering", nil)},
+		{Name: "attributes", Type: ast.NamedType("_AttributesJsonOrdering", nil)},
+	}}
 	tests := []struct {
 		name    string
 		input   interface{}
@@ -139,11 +146,52 @@ func TestCollectOrdering(t *testing.T) {
 			input: map[string]interface{}{"name": "DESC_NULL_FIRST"},
 			want:  []OrderField{{Key: "name", Type: OrderingTypesDescNull}},
 		},
+		{
+			name:  "distance",
+			input: map[string]interface{}{"location": map[string]interface{}{"from": "point", "direction": "DESC"}},
+			want:  []OrderField{{Key: "location", Type: OrderingTypesDesc, Value: "point"}},
+		},
+		{
+			name:  "distance_default_direction",
+			input: map[string]interface{}{"location": map[string]interface{}{"from": "point"}},
+			want:  []OrderField{{Key: "location", Type: OrderingTypesAsc, Value: "point"}},
+		},
+		{
+			name: "json_path",
+			input: map[string]interface{}{"attributes": map[string]interface{}{
+				"price":   "DESC",
+				"details": map[string]interface{}{"manufacturer": "ASC"},
+			}},
+			want: []OrderField{
+				{Key: "attributes", Type: OrderingTypesAsc, Path: []string{"details", "manufacturer"}},
+				{Key: "attributes", Type: OrderingTypesDesc, Path: []string{"price"}},
+			},
+		},
+		{
+			name:  "json_path_from_field",
+			input: map[string]interface{}{"attributes": map[string]interface{}{"from": "DESC"}},
+			want:  []OrderField{{Key: "attributes", Type: OrderingTypesDesc, Path: []string{"from"}}},
+		},
+		{
+			name:  "json_path_nested_from_object",
+			input: map[string]interface{}{"attributes": map[string]interface{}{"from": map[string]interface{}{"city": "ASC"}}},
+			want:  []OrderField{{Key: "attributes", Type: OrderingTypesAsc, Path: []string{"from", "city"}}},
+		},
+		{
+			name:    "unknown_type",
+			input:   "name",
+			wantErr: true,
+		},
+		{
+			name:    "slice_of_unknown_type",
+			input:   []interface{}{"name"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := CollectOrdering(tt.input)
+			got, err := CollectOrdering(tt.input, orderingDef)
 			if tt.wantErr {
 				require.Error(t, err)
 				return
@@ -162,6 +210,7 @@ func TestGetOperationType(t *testing.T) {
 	assert.Equal(t, OperationType("insert"), InsertOperation)
 	assert.Equal(t, OperationType("delete"), DeleteOperation)
 	assert.Equal(t, OperationType("update"), UpdateOperation)
+	assert.Equal(t, OperationType("function"), FunctionOperation)
 	assert.Equal(t, OperationType("unknown"), UnknownOperation)
 }
 
@@ -243,3 +292,57 @@ func TestOrderingTypes(t *testing.T) {
 func TestInputFieldName(t *testing.T) {
 	assert.Equal(t, "inputs", InputFieldName)
 }
+
+func TestCollectFromQuery_Errors(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type User {
+			id: Int!
+			name: String
+		}
+		type Query {
+			users: [User]
+		}
+	`})
+	tests := []struct {
+		name        string
+		query       string
+		variables   map[string]any
+		prepare     func(doc *ast.QueryDocument)
+		expectedErr string
+	}{
+		{
+			name:        "missing_fragment",
+			query:       `query { users { ...userFields } } fragment userFields on User { name }`,
+			prepare:     func(doc *ast.QueryDocument) { doc.Fragments = nil },
+			expectedErr: "missing fragment userFields",
+		},
+		{
+			name:        "skip_if_not_boolean",
+			query:       `query($skip: Boolean!) { users { name @skip(if: $skip) } }`,
+			variables:   map[string]any{"skip": "yes"},
+			expectedErr: "skip: argument 'if' is not a boolean",
+		},
+		{
+			name:        "include_in_fragment_if_not_boolean",
+			query:       `query($include: Boolean!) { users { ... on User @include(if: $include) { name } } }`,
+			variables:   map[string]any{"include": 1},
+			expectedErr: "include: argument 'if' is not a boolean",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, errs := gqlparser.LoadQuery(s, tt.query)
+			require.Nil(t, errs)
+			if tt.prepare != nil {
+				tt.prepare(doc)
+			}
+			sel := doc.Operations[0].SelectionSet[0].(*ast.Field)
+			opCtx := &graphql.OperationContext{Doc: doc, Variables: tt.variables}
+			_, err := CollectFromQuery(sel, s, opCtx, map[string]any{})
+			var gqlErr *Error
+			require.ErrorAs(t, err, &gqlErr)
+			assert.Equal(t, ErrorCodeValidation, gqlErr.Code)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package builders
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorCode classifies the errors of the execution layer, it's surfaced to clients as the extensions.code of the
+// GraphQL error
+type ErrorCode string
+
+const (
+	// ErrorCodeValidation is returned for arguments that can't be executed, i.e. an unsupported operator or an invalid
+	// value for a column
+	ErrorCodeValidation ErrorCode = "VALIDATION_FAILED"
+	// ErrorCodeNotFound is returned when the object of an operation doesn't exist
+	ErrorCodeNotFound ErrorCode = "NOT_FOUND"
+	// ErrorCodeConstraintViolation is returned when a mutation violates a constraint of the database, i.e. a unique
+	// or a foreign key constraint
+	ErrorCodeConstraintViolation ErrorCode = "CONSTRAINT_VIOLATION"
+	// ErrorCodePermission is returned when the database denied the operation
+	ErrorCodePermission ErrorCode = "PERMISSION_DENIED"
+	// ErrorCodeTimeout is returned when a query exceeded its statement timeout
+	ErrorCodeTimeout ErrorCode = "STATEMENT_TIMEOUT"
+	// ErrorCodeInternal is returned for any other failure, i.e. an invalid schema or an unavailable database
+	ErrorCodeInternal ErrorCode = "INTERNAL"
+)
+
+// Error is a typed error of the execution layer, executors return it as a GraphQL error with its Code in
+// extensions.code, use errors.As to retrieve it
+type Error struct {
+	Code    ErrorCode
+	Message string
+	// Column and Constraint are the column and the constraint of a constraint violation, if they are known
+	Column     string
+	Constraint string
+	// Err is the underlying error, i.e. the error of the database driver
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Extensions returns the extensions of the GraphQL error of e
+func (e *Error) Extensions() map[string]any {
+	extensions := map[string]any{"code": string(e.Code)}
+	if e.Column != "" {
+		extensions["column"] = e.Column
+	}
+	if e.Constraint != "" {
+		extensions["constraint"] = e.Constraint
+	}
+	return extensions
+}
+
+// ValidationErrorf returns an Error with ErrorCodeValidation
+func ValidationErrorf(format string, args ...any) *Error {
+	return newError(ErrorCodeValidation, format, args...)
+}
+
+// NotFoundErrorf returns an Error with ErrorCodeNotFound
+func NotFoundErrorf(format string, args ...any) *Error {
+	return newError(ErrorCodeNotFound, format, args...)
+}
+
+// InternalErrorf returns an Error with ErrorCodeInternal
+func InternalErrorf(format string, args ...any) *Error {
+	return newError(ErrorCodeInternal, format, args...)
+}
+
+// newError returns an Error of the code, wrapping the error of the format's %w verb if it has one
+func newError(code ErrorCode, format string, args ...any) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, Message: err.Error(), Err: errors.Unwrap(err)}
+}
+
+// GQLError returns err as a GraphQL error whose extensions.code is the code of the Error it wraps, errors that aren't
+// typed are internal errors. GraphQL errors are returned as is.
+func GQLError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		return err
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Code: ErrorCodeInternal, Message: err.Error(), Err: err}
+	}
+	return &gqlerror.Error{Err: err, Message: err.Error(), Extensions: e.Extensions()}
+}
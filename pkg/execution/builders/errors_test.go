@@ -0,0 +1,69 @@
+package builders
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestGQLError(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []struct {
+		name       string
+		err        error
+		message    string
+		extensions map[string]any
+	}{
+		{
+			name:       "validation",
+			err:        ValidationErrorf("key operator %s not supported", "near"),
+			message:    "key operator near not supported",
+			extensions: map[string]any{"code": "VALIDATION_FAILED"},
+		},
+		{
+			name:       "wrapped_typed_error",
+			err:        fmt.Errorf("failed to build relation for posts: %w", NotFoundErrorf("post not found")),
+			message:    "failed to build relation for posts: post not found",
+			extensions: map[string]any{"code": "NOT_FOUND"},
+		},
+		{
+			name: "constraint_violation",
+			err: &Error{Code: ErrorCodeConstraintViolation, Message: "duplicate key value", Column: "email",
+				Constraint: "users_email_key"},
+			message:    "duplicate key value",
+			extensions: map[string]any{"code": "CONSTRAINT_VIOLATION", "column": "email", "constraint": "users_email_key"},
+		},
+		{
+			name:       "untyped_error",
+			err:        cause,
+			message:    "connection refused",
+			extensions: map[string]any{"code": "INTERNAL"},
+		},
+		{
+			name:       "internal_error_wrapping_cause",
+			err:        InternalErrorf("failed to connect: %w", cause),
+			message:    "failed to connect: connection refused",
+			extensions: map[string]any{"code": "INTERNAL"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := GQLError(tc.err)
+			var gqlErr *gqlerror.Error
+			require.ErrorAs(t, err, &gqlErr)
+			assert.Equal(t, tc.message, gqlErr.Message)
+			assert.Equal(t, tc.extensions, gqlErr.Extensions)
+			// the typed error and its cause can still be matched
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	assert.ErrorIs(t, GQLError(InternalErrorf("failed to connect: %w", cause)), cause)
+	gqlErr := gqlerror.Errorf("already a GraphQL error")
+	assert.Same(t, gqlErr, GQLError(gqlErr))
+	assert.NoError(t, GQLError(nil))
+}
@@ -3,6 +3,8 @@ package builders
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/roneli/fastgql/pkg/schema"
@@ -27,11 +29,12 @@ const (
 type OperationType string
 
 const (
-	QueryOperation   OperationType = "query"
-	InsertOperation  OperationType = "insert"
-	DeleteOperation  OperationType = "delete"
-	UpdateOperation  OperationType = "update"
-	UnknownOperation OperationType = "unknown"
+	QueryOperation    OperationType = "query"
+	InsertOperation   OperationType = "insert"
+	DeleteOperation   OperationType = "delete"
+	UpdateOperation   OperationType = "update"
+	FunctionOperation OperationType = "function"
+	UnknownOperation  OperationType = "unknown"
 )
 
 type Fields []Field
@@ -59,6 +62,22 @@ type Field struct {
 	TypeDefinition *ast.Definition
 	// Parent field of this field
 	Parent *Field
+	// RemoteRelations are relation fields whose type resides in a different dialect than this field's type,
+	// they are stripped from Selections and resolved in a separate batched query by the execution layer.
+	RemoteRelations Fields
+	// KeyFilter restricts the query to rows matching the given key values, it is used to batch resolve
+	// relations for many parent rows at once.
+	KeyFilter *KeyFilter
+}
+
+// KeyFilter restricts a query to rows whose Columns match any of the Values tuples,
+// each tuple in Values holds a value for each column in Columns.
+type KeyFilter struct {
+	Columns []string
+	// Fields are the GraphQL names of the key fields, they're converted to columns by the builder and used instead
+	// of Columns
+	Fields []string
+	Values [][]any
 }
 
 func NewField(parent *Field, field *ast.Field, schema *ast.Schema, args map[string]interface{}) Field {
@@ -92,8 +111,19 @@ func (f Field) Relation() *schema.RelationDirective {
 	return schema.GetRelationDirective(f.Definition)
 }
 
+// FastgqlField directive on field, if it exists
+func (f Field) FastgqlField() *schema.FastgqlFieldDirective {
+	if f.Definition == nil {
+		return nil
+	}
+	return schema.GetFastgqlFieldDirective(f.Definition)
+}
+
 // Table directive on field, if it exists
 func (f Field) Table() *schema.TableDirective {
+	if f.TypeDefinition == nil {
+		return nil
+	}
 	t, err := schema.GetTableDirective(f.TypeDefinition)
 	if err != nil {
 		return nil
@@ -101,6 +131,14 @@ func (f Field) Table() *schema.TableDirective {
 	return t
 }
 
+// Dialect of the field's type as defined in the @table directive, defaults to "postgres"
+func (f Field) Dialect() string {
+	if t := f.Table(); t != nil && t.Dialect != "" {
+		return t.Dialect
+	}
+	return defaultDialect
+}
+
 func GetFilterInput(s *ast.Schema, f *ast.Definition) *ast.Definition {
 	return s.Types[fmt.Sprintf("%sFilterInput", f.Name)]
 }
@@ -111,6 +149,8 @@ func GetOperationType(ctx context.Context) OperationType {
 		sel := opCtx.Operation.SelectionSet[0]
 		field := sel.(*ast.Field)
 		switch {
+		case field.Definition != nil && schema.GetSQLMutationDirective(field.Definition) != nil:
+			return FunctionOperation
 		case strings.HasPrefix(field.Name, "delete"):
 			return DeleteOperation
 		case strings.HasPrefix(field.Name, "create"):
@@ -129,26 +169,31 @@ func GetAggregateField(parentField, aggField Field) Field {
 	return f
 }
 
-func CollectOrdering(ordering interface{}) ([]OrderField, error) {
+// CollectOrdering collects the order fields of an orderBy argument. orderingDef is the ordering input type of the
+// argument, its fields of the _DistanceOrdering type are ordered by distance and other input values are orderings of
+// the nested fields of typed JSON fields. If orderingDef is nil no field is ordered by distance.
+func CollectOrdering(ordering interface{}, orderingDef *ast.Definition) ([]OrderField, error) {
 	switch orderings := ordering.(type) {
 	case map[string]interface{}:
-		return buildOrderingHelper(orderings), nil
+		return buildOrderingHelper(orderings, orderingDef), nil
 	case []interface{}:
 		var orderFields []OrderField
 		for _, o := range orderings {
 			argMap, ok := o.(map[string]interface{})
 			if !ok {
-				return nil, fmt.Errorf("invalid value type")
+				return nil, ValidationErrorf("invalid ordering value type %T", o)
 			}
-			orderFields = append(orderFields, buildOrderingHelper(argMap)...)
+			orderFields = append(orderFields, buildOrderingHelper(argMap, orderingDef)...)
 		}
 		return orderFields, nil
 	default:
-		panic(fmt.Sprintf("unknown ordering type %v", orderings))
+		return nil, ValidationErrorf("unknown ordering type %T", orderings)
 	}
 }
 
-func CollectFields(ctx context.Context, schema *ast.Schema) Field {
+// CollectFields collects the field of the context's resolver and its selections, it fails with a validation error if
+// the selections reference a missing fragment or a @skip/@include directive without a boolean if argument
+func CollectFields(ctx context.Context, schema *ast.Schema) (Field, error) {
 	resCtx := graphql.GetFieldContext(ctx)
 	opCtx := graphql.GetOperationContext(ctx)
 	filterCtx := GetFieldFilterContext(ctx)
@@ -163,14 +208,62 @@ func CollectFields(ctx context.Context, schema *ast.Schema) Field {
 		}
 	}
 	f := NewField(nil, resCtx.Field.Field, schema, args)
-	f.Selections = collectFields(&f, f.SelectionSet, schema, opCtx, make(map[string]bool))
-	return f
+	selections, err := collectFields(&f, f.SelectionSet, schema, opCtx, make(map[string]bool))
+	if err != nil {
+		return Field{}, err
+	}
+	f.Selections = selections
+	return f, nil
 }
 
-func CollectFromQuery(field *ast.Field, schema *ast.Schema, opCtx *graphql.OperationContext, args map[string]interface{}) Field {
+func CollectFromQuery(field *ast.Field, schema *ast.Schema, opCtx *graphql.OperationContext, args map[string]interface{}) (Field, error) {
 	f := NewField(nil, field, schema, args)
-	f.Selections = collectFields(&f, f.SelectionSet, schema, opCtx, make(map[string]bool))
-	return f
+	selections, err := collectFields(&f, f.SelectionSet, schema, opCtx, make(map[string]bool))
+	if err != nil {
+		return Field{}, err
+	}
+	f.Selections = selections
+	return f, nil
+}
+
+// CollectEntityFields collects the fields selected on the typeName entity by an Apollo Federation _entities field,
+// the returned field is a list of typeName, fragments on other entity types are skipped
+func CollectEntityFields(ctx context.Context, schema *ast.Schema, typeName string) (Field, error) {
+	resCtx := graphql.GetFieldContext(ctx)
+	opCtx := graphql.GetOperationContext(ctx)
+	entities := resCtx.Field.Field
+	field := &ast.Field{
+		Alias:            entities.Alias,
+		Name:             entities.Name,
+		SelectionSet:     entitySelections(entities.SelectionSet, typeName, opCtx.Doc),
+		Definition:       &ast.FieldDefinition{Name: entities.Name, Type: ast.ListType(ast.NamedType(typeName, nil), nil)},
+		ObjectDefinition: entities.ObjectDefinition,
+	}
+	f := NewField(nil, field, schema, map[string]any{})
+	selections, err := collectFields(&f, f.SelectionSet, schema, opCtx, make(map[string]bool))
+	if err != nil {
+		return Field{}, err
+	}
+	f.Selections = selections
+	return f, nil
+}
+
+// entitySelections returns the fragments on typeName of an _entities field selection set
+func entitySelections(selectionSet ast.SelectionSet, typeName string, doc *ast.QueryDocument) ast.SelectionSet {
+	var selections ast.SelectionSet
+	for _, sel := range selectionSet {
+		switch sel := sel.(type) {
+		case *ast.InlineFragment:
+			if sel.TypeCondition == typeName {
+				selections = append(selections, sel)
+			}
+		case *ast.FragmentSpread:
+			if fragment := doc.Fragments.ForName(sel.Name); fragment != nil && fragment.TypeCondition == typeName {
+				selections = append(selections, sel)
+			}
+		}
+	}
+	return selections
 }
 
 func getTypeName(f *ast.Field) string {
@@ -182,12 +275,16 @@ func getTypeName(f *ast.Field) string {
 	return typeName
 }
 
-func collectFields(parent *Field, selectionSet ast.SelectionSet, schema *ast.Schema, opCtx *graphql.OperationContext, visited map[string]bool) []Field {
+func collectFields(parent *Field, selectionSet ast.SelectionSet, schema *ast.Schema, opCtx *graphql.OperationContext, visited map[string]bool) ([]Field, error) {
 	groupedFields := make([]Field, 0)
 	for _, sel := range selectionSet {
 		switch sel := sel.(type) {
 		case *ast.Field:
-			if !shouldIncludeNode(sel.Directives, opCtx.Variables) {
+			include, err := shouldIncludeNode(sel.Directives, opCtx.Variables)
+			if err != nil {
+				return nil, err
+			}
+			if !include {
 				continue
 			}
 			if sel.Name == "__typename" {
@@ -197,36 +294,58 @@ func collectFields(parent *Field, selectionSet ast.SelectionSet, schema *ast.Sch
 				return NewField(parent, sel, schema, resolveArguments(sel, opCtx.Variables))
 			})
 			// Add filter fields for relation from different provider, so they are returned by builder query
-			if selField.FieldType == TypeRelation && selField.Table().Dialect != parent.Table().Dialect {
-				for _, relationField := range selField.Relation().Fields {
-					groupedFields = append(groupedFields, Field{
-						Field: &ast.Field{
-							Name:             strcase.ToLowerCamel(relationField),
-							ObjectDefinition: parent.ObjectDefinition,
-						},
-						FieldType: TypeScalar,
-						Parent:    parent,
-					})
+			if selField.FieldType == TypeRelation && selField.Dialect() != parent.Dialect() {
+				remote := *selField
+				selections, err := collectFields(&remote, remote.SelectionSet, schema, opCtx, map[string]bool{})
+				if err != nil {
+					return nil, err
 				}
-				// No need to add the original selection as it exists in a different source
+				remote.Selections = selections
+				// No need to add the original selection as it exists in a different source, instead it's resolved
+				// by the execution layer using the relation key fields
+				groupedFields = groupedFields[:len(groupedFields)-1]
+				addRemoteRelation(parent, remote)
+				groupedFields = addKeyFields(groupedFields, parent, selField.Relation().Fields)
+				continue
+			}
+			// Fields marked with skipSelect are resolved manually, only the keys they depend on are selected
+			if d := selField.FastgqlField(); d != nil && d.SkipSelect {
+				groupedFields = groupedFields[:len(groupedFields)-1]
+				groupedFields = addKeyFields(groupedFields, parent, d.Keys)
 				continue
 			}
 			if selField.SelectionSet != nil {
 				// Add any sub selections of this field
-				selField.Selections = append(selField.Selections, collectFields(selField, selField.SelectionSet, schema, opCtx, map[string]bool{})...)
+				selections, err := collectFields(selField, selField.SelectionSet, schema, opCtx, map[string]bool{})
+				if err != nil {
+					return nil, err
+				}
+				selField.Selections = append(selField.Selections, selections...)
 			}
 		case *ast.InlineFragment:
-			if !shouldIncludeNode(sel.Directives, opCtx.Variables) {
+			include, err := shouldIncludeNode(sel.Directives, opCtx.Variables)
+			if err != nil {
+				return nil, err
+			}
+			if !include {
 				continue
 			}
 
-			for _, childField := range collectFields(parent, sel.SelectionSet, schema, opCtx, visited) {
+			childFields, err := collectFields(parent, sel.SelectionSet, schema, opCtx, visited)
+			if err != nil {
+				return nil, err
+			}
+			for _, childField := range childFields {
 				f := getOrCreateAndAppendField(&groupedFields, childField.Name, childField.ObjectDefinition, func() Field { return childField })
 				f.Selections = append(f.Selections, childField.Selections...)
 			}
 
 		case *ast.FragmentSpread:
-			if !shouldIncludeNode(sel.Directives, opCtx.Variables) {
+			include, err := shouldIncludeNode(sel.Directives, opCtx.Variables)
+			if err != nil {
+				return nil, err
+			}
+			if !include {
 				continue
 			}
 			fragmentName := sel.Name
@@ -237,20 +356,55 @@ func collectFields(parent *Field, selectionSet ast.SelectionSet, schema *ast.Sch
 
 			fragment := opCtx.Doc.Fragments.ForName(fragmentName)
 			if fragment == nil {
-				// should never happen, validator has already run
-				panic(fmt.Errorf("missing fragment %s", fragmentName))
+				return nil, ValidationErrorf("missing fragment %s", fragmentName)
 			}
 
-			for _, childField := range collectFields(parent, fragment.SelectionSet, schema, opCtx, visited) {
+			childFields, err := collectFields(parent, fragment.SelectionSet, schema, opCtx, visited)
+			if err != nil {
+				return nil, err
+			}
+			for _, childField := range childFields {
 				f := getOrCreateAndAppendField(&groupedFields, childField.Name, childField.ObjectDefinition, func() Field { return childField })
 				f.Selections = append(f.Selections, childField.Selections...)
 			}
 		default:
-			panic(fmt.Errorf("unsupported %T", sel))
+			return nil, InternalErrorf("unsupported selection %T", sel)
 		}
 	}
 
-	return groupedFields
+	return groupedFields, nil
+}
+
+// addKeyFields adds scalar fields for the given key columns to fields, unless they are already selected
+func addKeyFields(fields []Field, parent *Field, columns []string) []Field {
+	for _, c := range columns {
+		keyName := strcase.ToLowerCamel(c)
+		if Fields(fields).HasSelection(keyName) {
+			continue
+		}
+		fields = append(fields, Field{
+			Field: &ast.Field{
+				Name:             keyName,
+				Alias:            keyName,
+				ObjectDefinition: parent.ObjectDefinition,
+			},
+			FieldType: TypeScalar,
+			Parent:    parent,
+		})
+	}
+	return fields
+}
+
+// addRemoteRelation adds the relation field to the parent's remote relations, merging selections of relations
+// that were selected more than once (i.e. via fragments)
+func addRemoteRelation(parent *Field, remote Field) {
+	for i, r := range parent.RemoteRelations {
+		if r.Alias == remote.Alias {
+			parent.RemoteRelations[i].Selections = append(parent.RemoteRelations[i].Selections, remote.Selections...)
+			return
+		}
+	}
+	parent.RemoteRelations = append(parent.RemoteRelations, remote)
 }
 
 func getOrCreateAndAppendField(c *[]Field, name string, objectDefinition *ast.Definition, creator func() Field) *Field {
@@ -264,38 +418,44 @@ func getOrCreateAndAppendField(c *[]Field, name string, objectDefinition *ast.De
 	return &(*c)[len(*c)-1]
 }
 
-func shouldIncludeNode(directives ast.DirectiveList, variables map[string]interface{}) bool {
+func shouldIncludeNode(directives ast.DirectiveList, variables map[string]interface{}) (bool, error) {
 	if len(directives) == 0 {
-		return true
+		return true, nil
 	}
 
 	skip, include := false, true
 
 	if d := directives.ForName("skip"); d != nil {
-		skip = resolveIfArgument(d, variables)
+		var err error
+		if skip, err = resolveIfArgument(d, variables); err != nil {
+			return false, err
+		}
 	}
 
 	if d := directives.ForName("include"); d != nil {
-		include = resolveIfArgument(d, variables)
+		var err error
+		if include, err = resolveIfArgument(d, variables); err != nil {
+			return false, err
+		}
 	}
 
-	return !skip && include
+	return !skip && include, nil
 }
 
-func resolveIfArgument(d *ast.Directive, variables map[string]interface{}) bool {
+func resolveIfArgument(d *ast.Directive, variables map[string]interface{}) (bool, error) {
 	arg := d.Arguments.ForName("if")
 	if arg == nil {
-		panic(fmt.Sprintf("%s: argument 'if' not defined", d.Name))
+		return false, ValidationErrorf("%s: argument 'if' not defined", d.Name)
 	}
 	value, err := arg.Value.Value(variables)
 	if err != nil {
-		panic(err)
+		return false, ValidationErrorf("%s: %w", d.Name, err)
 	}
 	ret, ok := value.(bool)
 	if !ok {
-		panic(fmt.Sprintf("%s: argument 'if' is not a boolean", d.Name))
+		return false, ValidationErrorf("%s: argument 'if' is not a boolean", d.Name)
 	}
-	return ret
+	return ret, nil
 }
 
 func resolveArguments(f *ast.Field, variables map[string]interface{}) map[string]interface{} {
@@ -305,9 +465,28 @@ func resolveArguments(f *ast.Field, variables map[string]interface{}) map[string
 	return f.ArgumentMap(variables)
 }
 
-func buildOrderingHelper(argMap map[string]interface{}) []OrderField {
+func buildOrderingHelper(argMap map[string]interface{}, orderingDef *ast.Definition) []OrderField {
 	orderFields := make([]OrderField, 0)
 	for k, v := range argMap {
+		m, ok := v.(map[string]interface{})
+		// typed JSON fields are ordered by their nested fields, i.e. {attributes: {details: {price: DESC}}}
+		if ok && !isDistanceOrdering(orderingDef, k) {
+			orderFields = append(orderFields, jsonPathOrderings(k, nil, m)...)
+			continue
+		}
+		// distance orderings are given as {from: <point>, direction: <ordering type>}
+		if ok {
+			direction := OrderingTypesAsc
+			if d, ok := m["direction"]; ok && d != nil {
+				direction = OrderingTypes(cast.ToString(d))
+			}
+			orderFields = append(orderFields, OrderField{
+				Key:   k,
+				Type:  direction,
+				Value: m["from"],
+			})
+			continue
+		}
 		orderFields = append(orderFields, OrderField{
 			Key:  k,
 			Type: OrderingTypes(cast.ToString(v)),
@@ -316,6 +495,46 @@ func buildOrderingHelper(argMap map[string]interface{}) []OrderField {
 	return orderFields
 }
 
+// isDistanceOrdering checks if the key of the ordering input is a distance ordering {from: <point>, direction: <ordering type>}
+// rather than the ordering of a typed JSON field's nested fields
+func isDistanceOrdering(orderingDef *ast.Definition, key string) bool {
+	if orderingDef == nil {
+		return false
+	}
+	f := orderingDef.Fields.ForName(key)
+	return f != nil && f.Type.Name() == schema.DistanceOrderingName
+}
+
+// OrderingDefinition returns the ordering input type of the field's orderBy argument, or nil if it has none
+func (f Field) OrderingDefinition(s *ast.Schema) *ast.Definition {
+	if f.Field == nil || f.Definition == nil || s == nil {
+		return nil
+	}
+	arg := f.Definition.Arguments.ForName(string(schema.OrderBy))
+	if arg == nil {
+		return nil
+	}
+	return s.Types[arg.Type.Name()]
+}
+
+// jsonPathOrderings flattens the ordering of a typed JSON field to an OrderField for each ordered nested field
+func jsonPathOrderings(key string, path []string, m map[string]interface{}) []OrderField {
+	var orderFields []OrderField
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		p := append(slices.Clone(path), k)
+		if nested, ok := m[k].(map[string]interface{}); ok {
+			orderFields = append(orderFields, jsonPathOrderings(key, p, nested)...)
+			continue
+		}
+		orderFields = append(orderFields, OrderField{
+			Key:  key,
+			Type: OrderingTypes(cast.ToString(m[k])),
+			Path: p,
+		})
+	}
+	return orderFields
+}
+
 // parseFieldType returns the fieldType based on the name/directive or type of the *ast.Field
 func parseFieldType(field *ast.Field, typeDef *ast.Definition) fieldType {
 	switch {
@@ -96,10 +96,8 @@ func (b Builder) buildFilterExp(_ builders.Field, filters map[string]interface{}
 	var allFilters bson.D
 	for k, v := range filters {
 		switch {
-		case k == string(builders.LogicalOperatorAND) || k == string(builders.LogicalOperatorOR):
-			panic("not implemented")
-		case k == string(builders.LogicalOperatorNot):
-			panic("not implemented")
+		case k == string(builders.LogicalOperatorAND) || k == string(builders.LogicalOperatorOR) || k == string(builders.LogicalOperatorNot):
+			return nil, builders.ValidationErrorf("logical operator %s is not supported by mongo", k)
 		default:
 			opMap, ok := v.(map[string]interface{})
 			if !ok {
@@ -120,7 +118,7 @@ func (b Builder) buildFilterExp(_ builders.Field, filters map[string]interface{}
 func (b Builder) Operation(fieldName, operatorName string, value interface{}) (bson.E, error) {
 	opFunc, ok := b.Operators[operatorName]
 	if !ok {
-		return bson.E{}, fmt.Errorf("key operator %s not supported", operatorName)
+		return bson.E{}, builders.ValidationErrorf("key operator %s not supported", operatorName)
 	}
 	return opFunc(fieldName, operatorName, value), nil
 }
@@ -145,7 +143,7 @@ func (b Builder) doBuildProjection(field builders.Field) (bson.M, error) {
 
 			subProjection, err := b.doBuildProjection(childField)
 			if err != nil {
-				return nil, fmt.Errorf("failed to build relation for %s", childField.Name)
+				return nil, fmt.Errorf("failed to build relation for %s: %w", childField.Name, err)
 			}
 			for k, v := range subProjection {
 				projection[childField.Name+"."+k] = v
@@ -153,14 +151,14 @@ func (b Builder) doBuildProjection(field builders.Field) (bson.M, error) {
 		case builders.TypeAggregate:
 			aggP, err := b.buildProjectionAggregate(field, childField)
 			if err != nil {
-				return nil, fmt.Errorf("failed to build relation for %s", childField.Name)
+				return nil, fmt.Errorf("failed to build relation for %s: %w", childField.Name, err)
 			}
 			for k, v := range aggP {
 				projection[k] = v
 			}
 		default:
 			b.Logger.Error("unknown field type", "fieldName", childField.Name, "fieldType", childField.FieldType)
-			panic("unknown field type")
+			return nil, builders.InternalErrorf("unknown field type %v of %s", childField.FieldType, childField.Name)
 		}
 	}
 	return projection, nil
@@ -176,7 +174,7 @@ func (b Builder) buildProjectionAggregate(parentField, field builders.Field) (bs
 		case "count":
 			ap[field.Name+".count"] = bson.D{{Key: "$size", Value: bson.M{"$cond": bson.M{"if": bson.D{{Key: "$isArray", Value: f}}, "then": f, "else": bson.A{}}}}}
 		default:
-			panic("unknown aggregation selection")
+			return nil, builders.ValidationErrorf("unknown aggregation selection %s", s.Name)
 		}
 	}
 	return ap, nil
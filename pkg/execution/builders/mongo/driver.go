@@ -27,7 +27,10 @@ func NewDriver(dialect string, cfg *builders.Config, uri string) *Driver {
 }
 
 func (d Driver) Scan(ctx context.Context, out interface{}) error {
-	field := builders.CollectFields(ctx, d.builder.Schema)
+	field, err := builders.CollectFields(ctx, d.builder.Schema)
+	if err != nil {
+		return err
+	}
 	table := field.Table()
 	db := d.client.Database(table.Schema)
 	collection := db.Collection(table.Name)
@@ -5,46 +5,87 @@ import (
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
-	"github.com/iancoleman/strcase"
+	"github.com/spf13/cast"
 
 	"github.com/roneli/fastgql/pkg/execution/builders"
 )
 
-var defaultAggregatorOperators = map[string]builders.AggregatorOperator{
+var defaultAggregates = map[string]builders.AggregateFunc{
 	"max": aggMax,
 	"min": aggMin,
 	"avg": aggAvg,
 	"sum": aggSum,
 }
 
-func aggSum(table exp.AliasedExpression, fields []builders.Field) (goqu.Expression, error) {
-	sumFields := make([]interface{}, 0, len(fields)*2)
-	for _, f := range fields {
-		sumFields = append(sumFields, goqu.L(fmt.Sprintf("'%s'", f.Name)), goqu.SUM(table.Col(strcase.ToSnake(f.Name))))
-	}
-	return goqu.Func("json_build_object", sumFields...), nil
+// expressionOperators are the comparator operators supported on SQL expressions, i.e. aggregates in having and
+// computed fields
+var expressionOperators = map[string]func(agg exp.LiteralExpression, value interface{}) exp.Expression{
+	"eq":    func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.Eq(value) },
+	"neq":   func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.Neq(value) },
+	"gt":    func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.Gt(value) },
+	"gte":   func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.Gte(value) },
+	"lt":    func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.Lt(value) },
+	"lte":   func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.Lte(value) },
+	"in":    func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.In(value) },
+	"notIn": func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.NotIn(value) },
+	"like":  func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.Like(value) },
+	"ilike": func(agg exp.LiteralExpression, value interface{}) exp.Expression { return agg.ILike(value) },
+	"prefix": func(agg exp.LiteralExpression, value interface{}) exp.Expression {
+		return agg.Like(fmt.Sprintf("%s%%", value))
+	},
+	"suffix": func(agg exp.LiteralExpression, value interface{}) exp.Expression {
+		return agg.Like(fmt.Sprintf("%%%s", value))
+	},
+	"between": func(agg exp.LiteralExpression, value interface{}) exp.Expression {
+		r := cast.ToStringMap(value)
+		return agg.Between(goqu.Range(r["from"], r["to"]))
+	},
+	"isNull": func(agg exp.LiteralExpression, value interface{}) exp.Expression {
+		if cast.ToBool(value) {
+			return agg.IsNull()
+		}
+		return agg.IsNotNull()
+	},
 }
 
-func aggAvg(table exp.AliasedExpression, fields []builders.Field) (goqu.Expression, error) {
-	avgFields := make([]interface{}, 0, len(fields)*2)
-	for _, f := range fields {
-		avgFields = append(avgFields, goqu.L(fmt.Sprintf("'%s'", f.Name)), goqu.AVG(table.Col(strcase.ToSnake(f.Name))))
+// buildExpressionOperations builds the expressions of an SQL expression's comparator, i.e. {gt: 1, lt: 10}
+func buildExpressionOperations(agg exp.LiteralExpression, comparator interface{}) ([]exp.Expression, error) {
+	opMap, ok := comparator.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected comparator to be a map got %T", comparator)
 	}
-	return goqu.Func("json_build_object", avgFields...), nil
+	exps := make([]exp.Expression, 0, len(opMap))
+	for _, op := range sortedKeys(opMap) {
+		opFunc, ok := expressionOperators[op]
+		if !ok {
+			return nil, builders.ValidationErrorf("operator %s not supported", op)
+		}
+		exps = append(exps, opFunc(agg, opMap[op]))
+	}
+	return exps, nil
 }
 
-func aggMax(table exp.AliasedExpression, fields []builders.Field) (goqu.Expression, error) {
-	maxFields := make([]interface{}, 0, len(fields)*2)
+// buildAggregateObject builds a json object of the aggregate of each field's column, i.e. json_build_object('price', MAX(price))
+func buildAggregateObject(table exp.AliasedExpression, fields []builders.Field, agg builders.AggregateFunc, args map[string]any, caseConverter builders.ColumnCaseConverter) goqu.Expression {
+	aggFields := make([]interface{}, 0, len(fields)*2)
 	for _, f := range fields {
-		maxFields = append(maxFields, goqu.L(fmt.Sprintf("'%s'", f.Name)), goqu.MAX(table.Col(strcase.ToSnake(f.Name))))
+		aggFields = append(aggFields, goqu.L(fmt.Sprintf("'%s'", f.Name)), agg(table.Col(caseConverter(f.Name)), args))
 	}
-	return goqu.Func("json_build_object", maxFields...), nil
+	return goqu.Func("json_build_object", aggFields...)
 }
 
-func aggMin(table exp.AliasedExpression, fields []builders.Field) (goqu.Expression, error) {
-	minFields := make([]interface{}, 0, len(fields)*2)
-	for _, f := range fields {
-		minFields = append(minFields, goqu.L(fmt.Sprintf("'%s'", f.Name)), goqu.MIN(table.Col(strcase.ToSnake(f.Name))))
-	}
-	return goqu.Func("json_build_object", minFields...), nil
+func aggSum(col exp.IdentifierExpression, _ map[string]any) goqu.Expression {
+	return goqu.SUM(col)
+}
+
+func aggAvg(col exp.IdentifierExpression, _ map[string]any) goqu.Expression {
+	return goqu.AVG(col)
+}
+
+func aggMax(col exp.IdentifierExpression, _ map[string]any) goqu.Expression {
+	return goqu.MAX(col)
+}
+
+func aggMin(col exp.IdentifierExpression, _ map[string]any) goqu.Expression {
+	return goqu.MIN(col)
 }
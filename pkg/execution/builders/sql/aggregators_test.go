@@ -1,10 +1,12 @@
 package sql
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/iancoleman/strcase"
 	"github.com/roneli/fastgql/pkg/execution/builders"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -48,11 +50,10 @@ func TestAggregators(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			agg, ok := defaultAggregatorOperators[tt.aggregator]
+			agg, ok := defaultAggregates[tt.aggregator]
 			require.True(t, ok, "aggregator %s should exist", tt.aggregator)
 
-			expr, err := agg(table, fields)
-			require.NoError(t, err)
+			expr := buildAggregateObject(table, fields, agg, nil, strcase.ToSnake)
 
 			sql, _, err := goqu.Dialect("postgres").Select(expr).ToSQL()
 			require.NoError(t, err)
@@ -69,7 +70,7 @@ func TestAggregatorsSingleField(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		aggFunc     func(exp.AliasedExpression, []builders.Field) (goqu.Expression, error)
+		aggFunc     builders.AggregateFunc
 		expectedSQL string
 	}{
 		{
@@ -96,8 +97,7 @@ func TestAggregatorsSingleField(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expr, err := tt.aggFunc(table, fields)
-			require.NoError(t, err)
+			expr := buildAggregateObject(table, fields, tt.aggFunc, nil, strcase.ToSnake)
 
 			sql, _, err := goqu.Dialect("postgres").Select(expr).ToSQL()
 			require.NoError(t, err)
@@ -116,7 +116,7 @@ func TestAggregatorsMultipleFields(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		aggFunc     func(exp.AliasedExpression, []builders.Field) (goqu.Expression, error)
+		aggFunc     builders.AggregateFunc
 		expectedSQL string
 	}{
 		{
@@ -143,8 +143,7 @@ func TestAggregatorsMultipleFields(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expr, err := tt.aggFunc(table, fields)
-			require.NoError(t, err)
+			expr := buildAggregateObject(table, fields, tt.aggFunc, nil, strcase.ToSnake)
 
 			sql, _, err := goqu.Dialect("postgres").Select(expr).ToSQL()
 			require.NoError(t, err)
@@ -159,7 +158,7 @@ func TestAggregatorsEmptyFields(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		aggFunc     func(exp.AliasedExpression, []builders.Field) (goqu.Expression, error)
+		aggFunc     builders.AggregateFunc
 		expectedSQL string
 	}{
 		{
@@ -186,8 +185,7 @@ func TestAggregatorsEmptyFields(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expr, err := tt.aggFunc(table, fields)
-			require.NoError(t, err)
+			expr := buildAggregateObject(table, fields, tt.aggFunc, nil, strcase.ToSnake)
 
 			sql, _, err := goqu.Dialect("postgres").Select(expr).ToSQL()
 			require.NoError(t, err)
@@ -201,12 +199,12 @@ func TestAllDefaultAggregatorsExist(t *testing.T) {
 
 	for _, aggName := range expectedAggregators {
 		t.Run(aggName, func(t *testing.T) {
-			_, ok := defaultAggregatorOperators[aggName]
-			assert.True(t, ok, "aggregator %s should exist in defaultAggregatorOperators", aggName)
+			_, ok := defaultAggregates[aggName]
+			assert.True(t, ok, "aggregator %s should exist in defaultAggregates", aggName)
 		})
 	}
 
-	assert.Len(t, defaultAggregatorOperators, len(expectedAggregators))
+	assert.Len(t, defaultAggregates, len(expectedAggregators))
 }
 
 func TestAggregatorSnakeCaseConversion(t *testing.T) {
@@ -220,10 +218,95 @@ func TestAggregatorSnakeCaseConversion(t *testing.T) {
 	// Verify snake_case conversion for column names in SQL
 	expectedSQL := `SELECT json_build_object('createdAt', SUM("t"."created_at"), 'updatedAt', SUM("t"."updated_at"), 'userId', SUM("t"."user_id"))`
 
-	expr, err := aggSum(table, fields)
-	require.NoError(t, err)
+	expr := buildAggregateObject(table, fields, aggSum, nil, strcase.ToSnake)
 
 	sql, _, err := goqu.Dialect("postgres").Select(expr).ToSQL()
 	require.NoError(t, err)
 	assert.Equal(t, expectedSQL, sql)
 }
+
+func TestAggregatorColumnCaseConverter(t *testing.T) {
+	table := goqu.T("test").As("t")
+	fields := []builders.Field{
+		{Field: &ast.Field{Name: "createdAt", Alias: "createdAt"}},
+	}
+
+	expr := buildAggregateObject(table, fields, aggMax, nil, strings.ToUpper)
+
+	sql, _, err := goqu.Dialect("postgres").Select(expr).ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT json_build_object('createdAt', MAX("t"."CREATEDAT"))`, sql)
+}
+
+func TestCustomAggregateArguments(t *testing.T) {
+	table := goqu.T("orders").As("o")
+	fields := []builders.Field{
+		{Field: &ast.Field{Name: "amount", Alias: "amount"}},
+	}
+	percentile := func(col exp.IdentifierExpression, args map[string]any) goqu.Expression {
+		return goqu.L("percentile_cont(?) WITHIN GROUP (ORDER BY ?)", args["p"], col)
+	}
+
+	expr := buildAggregateObject(table, fields, percentile, map[string]any{"p": 0.9}, strcase.ToSnake)
+
+	sql, _, err := goqu.Dialect("postgres").Select(expr).ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT json_build_object('amount', percentile_cont(0.9) WITHIN GROUP (ORDER BY "o"."amount"))`, sql)
+}
+
+func TestBuildHavingOperations(t *testing.T) {
+	table := goqu.T("posts").As("p")
+
+	tests := []struct {
+		name        string
+		agg         exp.LiteralExpression
+		comparator  interface{}
+		expectedSQL string
+		expectedErr bool
+	}{
+		{
+			name:        "count_range",
+			agg:         goqu.L("?", goqu.COUNT(goqu.L("1"))),
+			comparator:  map[string]any{"lt": 10, "gte": 2},
+			expectedSQL: `SELECT * HAVING ((COUNT(1) >= 2) AND (COUNT(1) < 10))`,
+		},
+		{
+			name:        "max_between",
+			agg:         goqu.L("?", goqu.MAX(table.Col("price"))),
+			comparator:  map[string]any{"between": map[string]any{"from": 1, "to": 5}},
+			expectedSQL: `SELECT * HAVING (MAX("p"."price") BETWEEN 1 AND 5)`,
+		},
+		{
+			name:        "min_prefix_and_is_null",
+			agg:         goqu.L("?", goqu.MIN(table.Col("name"))),
+			comparator:  map[string]any{"prefix": "a", "isNull": false},
+			expectedSQL: `SELECT * HAVING ((MIN("p"."name") IS NOT NULL) AND (MIN("p"."name") LIKE 'a%'))`,
+		},
+		{
+			name:        "unsupported_operator",
+			agg:         goqu.L("?", goqu.SUM(table.Col("price"))),
+			comparator:  map[string]any{"olderThan": "1 day"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid_comparator",
+			agg:         goqu.L("?", goqu.SUM(table.Col("price"))),
+			comparator:  1,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exps, err := buildExpressionOperations(tt.agg, tt.comparator)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			sql, _, err := goqu.Dialect("postgres").Select().Having(exps...).ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+		})
+	}
+}
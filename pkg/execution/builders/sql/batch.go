@@ -0,0 +1,135 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/roneli/fastgql/pkg/execution"
+)
+
+// batchedQuery is a read query of a root field waiting to be sent in the operation's batch, scan reads its rows
+// into dest
+type batchedQuery struct {
+	sql  string
+	args []any
+	dest any
+	scan func(rows pgx.Rows) (int, error)
+	// timeout is the statement timeout of the query, zero if it's unbounded
+	timeout time.Duration
+}
+
+// key identifies queries that return the same result into the same destination type, i.e. the same root field
+// requested twice under different aliases
+func (q *batchedQuery) key() string {
+	return fmt.Sprintf("%T\x00%s\x00%v", q.dest, q.sql, q.args)
+}
+
+type batchResult struct {
+	rows int
+	err  error
+}
+
+// batchQuery executes the query in the operation's batch if BatchQueries is enabled, the queries of sibling root
+// fields are collected with execution.Batch and sent to the database in a single round trip. Otherwise, or if the
+// operation has no batch, the query is executed on its own. Queries sent to different pools or bounded by different
+// statement timeouts are batched separately, so each query is bounded by its own timeout.
+func (e *Executor) batchQuery(ctx context.Context, q *batchedQuery) (int, error) {
+	pool := e.readPool(ctx)
+	if !e.config.BatchQueries {
+		return executeQuery(ctx, pool, q)
+	}
+	if !execution.HasBatch(ctx) {
+		e.batchWarning.Do(func() {
+			e.builder.Logger.Warn("BatchQueries is enabled but the operation has no batch, add execution.BatchExtension to the server")
+		})
+		return executeQuery(ctx, pool, q)
+	}
+	r, err := execution.Batch(ctx, batchName(pool, q.timeout), q, func(ctx context.Context, queries []*batchedQuery) ([]batchResult, error) {
+		return executeBatch(ctx, pool, queries)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return r.rows, r.err
+}
+
+// executeQuery executes a single query bounded by its statement timeout
+func executeQuery(ctx context.Context, db database, q *batchedQuery) (int, error) {
+	n, err := withStatementTimeout(ctx, db, q.timeout, func(ctx context.Context, db database) (int, error) {
+		rows, err := db.Query(ctx, q.sql, q.args...)
+		if err != nil {
+			return 0, err
+		}
+		return q.scan(rows)
+	})
+	return n, queryError(ctx, q.timeout, err)
+}
+
+// batchName returns the name of the batch of the queries sent to the pool with the statement timeout
+func batchName(pool *pgxpool.Pool, timeout time.Duration) string {
+	return fmt.Sprintf("sql.batch.%p.%s", pool, timeout)
+}
+
+// executeBatch sends the queries as a single pgx batch, duplicate queries are sent once and their results are
+// copied to each destination. The queries of a batch share the same statement timeout, which bounds the batch.
+func executeBatch(ctx context.Context, db database, queries []*batchedQuery) ([]batchResult, error) {
+	batch, first := planBatch(queries)
+	timeout := queries[0].timeout
+	var results []batchResult
+	_, err := withStatementTimeout(ctx, db, timeout, func(qctx context.Context, db database) (int, error) {
+		br := db.SendBatch(qctx, batch)
+		defer br.Close()
+		results = make([]batchResult, len(queries))
+		for i, q := range queries {
+			if first[i] != i {
+				continue
+			}
+			rows, err := br.Query()
+			if err != nil {
+				results[i].err = queryError(ctx, timeout, err)
+				continue
+			}
+			results[i].rows, results[i].err = q.scan(rows)
+			results[i].err = queryError(ctx, timeout, results[i].err)
+		}
+		return 0, nil
+	})
+	// the batch fails only if it wasn't sent, errors of its queries are reported per query and rows that were already
+	// scanned are kept even if the read-only transaction failed to commit
+	if results == nil {
+		return nil, queryError(ctx, timeout, err)
+	}
+	for i, q := range queries {
+		if first[i] == i {
+			continue
+		}
+		results[i] = results[first[i]]
+		if results[i].err == nil {
+			reflect.ValueOf(q.dest).Elem().Set(reflect.ValueOf(queries[first[i]].dest).Elem())
+		}
+	}
+	return results, nil
+}
+
+// planBatch queues the distinct queries in a batch, first holds the index of the first query identical to each query
+func planBatch(queries []*batchedQuery) (*pgx.Batch, []int) {
+	batch := &pgx.Batch{}
+	first := make([]int, len(queries))
+	queued := make(map[string]int, len(queries))
+	for i, q := range queries {
+		k := q.key()
+		if j, ok := queued[k]; ok {
+			first[i] = j
+			continue
+		}
+		queued[k] = i
+		first[i] = i
+		batch.Queue(q.sql, q.args...)
+	}
+	return batch, first
+}
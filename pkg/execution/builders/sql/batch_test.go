@@ -0,0 +1,102 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_planBatch(t *testing.T) {
+	var posts, samePosts []map[string]any
+	var users []map[string]any
+	var user map[string]any
+	queries := []*batchedQuery{
+		{sql: `SELECT "sq0"."id" AS "id" FROM "posts" AS "sq0" LIMIT $1`, args: []any{int64(10)}, dest: &posts},
+		{sql: `SELECT "sq0"."id" AS "id" FROM "users" AS "sq0" LIMIT $1`, args: []any{int64(10)}, dest: &users},
+		{sql: `SELECT "sq0"."id" AS "id" FROM "posts" AS "sq0" LIMIT $1`, args: []any{int64(10)}, dest: &samePosts},
+		{sql: `SELECT "sq0"."id" AS "id" FROM "posts" AS "sq0" LIMIT $1`, args: []any{int64(5)}, dest: &posts},
+		{sql: `SELECT "sq0"."id" AS "id" FROM "users" AS "sq0" LIMIT $1`, args: []any{int64(10)}, dest: &user},
+	}
+	batch, first := planBatch(queries)
+	// the same query with the same arguments and destination type is sent once
+	assert.Equal(t, []int{0, 1, 0, 3, 4}, first)
+	require.Len(t, batch.QueuedQueries, 4)
+	assert.Equal(t, queries[0].sql, batch.QueuedQueries[0].SQL)
+	assert.Equal(t, []any{int64(5)}, batch.QueuedQueries[2].Arguments)
+}
+
+// fakeBatchResults returns the results of each queued query in order
+type fakeBatchResults struct {
+	errs []error
+	next int
+}
+
+func (f *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected exec")
+}
+
+func (f *fakeBatchResults) Query() (pgx.Rows, error) {
+	i := f.next
+	f.next++
+	if i >= len(f.errs) {
+		return nil, errors.New("no result")
+	}
+	return nil, f.errs[i]
+}
+
+func (f *fakeBatchResults) QueryRow() pgx.Row {
+	return nil
+}
+
+func (f *fakeBatchResults) Close() error {
+	return nil
+}
+
+func Test_executeBatch(t *testing.T) {
+	queryErr := errors.New("relation does not exist")
+	newQuery := func(sql string, dest *[]string, rows ...string) *batchedQuery {
+		return &batchedQuery{sql: sql, dest: dest, timeout: time.Minute, scan: func(pgx.Rows) (int, error) {
+			*dest = rows
+			return len(rows), nil
+		}}
+	}
+	var posts, samePosts, users, missing, sameMissing []string
+	queries := []*batchedQuery{
+		newQuery(`SELECT "name" FROM "posts"`, &posts, "a", "b"),
+		newQuery(`SELECT "name" FROM "users"`, &users, "c"),
+		newQuery(`SELECT "name" FROM "posts"`, &samePosts, "ignored"),
+		newQuery(`SELECT "name" FROM "missing"`, &missing),
+		newQuery(`SELECT "name" FROM "missing"`, &sameMissing),
+	}
+	db := &fakeDatabase{results: &fakeBatchResults{errs: []error{nil, nil, queryErr}}}
+	results, err := executeBatch(context.Background(), db, queries)
+	require.NoError(t, err)
+	require.Len(t, db.batch.QueuedQueries, 3, "identical queries are sent once")
+	assert.Equal(t, []string{"BEGIN", "SET LOCAL statement_timeout = 60000", "BATCH", "COMMIT"}, db.statements, "the batch is bounded by the timeout of its queries")
+	assert.WithinDuration(t, time.Now().Add(time.Minute), db.deadline, time.Second)
+
+	// results of identical queries are copied to each destination
+	assert.Equal(t, []batchResult{{rows: 2}, {rows: 1}, {rows: 2}, {err: queryErr}, {err: queryErr}}, results)
+	assert.Equal(t, []string{"a", "b"}, posts)
+	assert.Equal(t, []string{"a", "b"}, samePosts)
+	assert.Equal(t, []string{"c"}, users)
+	assert.Empty(t, missing)
+	assert.Empty(t, sameMissing)
+}
+
+func Test_batchName(t *testing.T) {
+	pool, replica := &pgxpool.Pool{}, &pgxpool.Pool{}
+	assert.Equal(t, batchName(pool, time.Second), batchName(pool, time.Second))
+	// queries bounded by different timeouts, or unbounded, are sent in their own batch
+	assert.NotEqual(t, batchName(pool, time.Second), batchName(pool, 2*time.Second))
+	assert.NotEqual(t, batchName(pool, 0), batchName(pool, time.Second))
+	assert.NotEqual(t, batchName(pool, time.Second), batchName(replica, time.Second))
+}
@@ -52,10 +52,18 @@ func generateTableName(n int) string {
 }
 
 type Builder struct {
-	Schema              *ast.Schema
-	Logger              log.Logger
-	TableNameGenerator  builders.TableNameGenerator
-	Operators           map[string]builders.Operator
+	Schema             *ast.Schema
+	Logger             log.Logger
+	TableNameGenerator builders.TableNameGenerator
+	Operators          map[string]builders.Operator
+	ScalarOperators    map[string]map[string]builders.Operator
+	ListOperators      map[string]builders.Operator
+	// Aggregates build the aggregate payload fields, having and orderBy of aggregates by aggregate name
+	Aggregates map[string]builders.AggregateFunc
+	// AggregatorOperators build the aggregate payload fields of the same name instead of Aggregates.
+	//
+	// Deprecated: AggregatorOperators can't be used in having and orderBy of aggregates, set
+	// builders.Config CustomAggregates instead.
 	AggregatorOperators map[string]builders.AggregatorOperator
 	CaseConverter       builders.ColumnCaseConverter
 	Dialect             string
@@ -87,13 +95,23 @@ func NewBuilder(config *builders.Config) Builder {
 	for k, v := range config.CustomOperators {
 		operators[k] = v
 	}
+	aggregates := make(map[string]builders.AggregateFunc)
+	for k, v := range defaultAggregates {
+		aggregates[k] = v
+	}
+	for k, v := range config.CustomAggregates {
+		aggregates[k] = v
+	}
 
 	return Builder{
 		Schema:              config.Schema,
 		Logger:              l,
 		TableNameGenerator:  tableNameGenerator,
 		Operators:           operators,
-		AggregatorOperators: defaultAggregatorOperators,
+		ScalarOperators:     defaultScalarOperators,
+		ListOperators:       defaultListOperators,
+		Aggregates:          aggregates,
+		AggregatorOperators: make(map[string]builders.AggregatorOperator),
 		CaseConverter:       caseConverter,
 		Dialect:             dialect,
 	}
@@ -120,6 +138,11 @@ func (b Builder) Create(field builders.Field) (string, []any, error) {
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get input values: %w", err)
 	}
+	if tableDef.objType != nil && tableDef.objType.Kind == ast.Interface {
+		if kv, err = b.getInterfaceInputValues(tableDef.objType, kv); err != nil {
+			return "", nil, err
+		}
+	}
 	insertQuery, err := b.buildInsert(tableDef, kv)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to build delete query: %w", err)
@@ -171,6 +194,24 @@ func (b Builder) Update(field builders.Field) (string, []any, error) {
 	return sql, args, err
 }
 
+// SQLMutation generates an SQL query calling the function of a @sqlMutation field. The function is called once in a
+// CTE, and the rows it returns are selected as the field's type, including relations of the selection set.
+func (b Builder) SQLMutation(field builders.Field) (string, []any, error) {
+	tableDef := getSQLMutationTableName(b.Schema, field.Definition, field.Arguments)
+	if tableDef.function == nil {
+		return "", nil, fmt.Errorf("mutation %s is missing the @sqlMutation directive", field.Name)
+	}
+	withTable := goqu.T(b.CaseConverter(field.Name))
+	query, err := b.buildQuery(tableDefinition{name: withTable.GetTable(), objType: tableDef.objType}, field)
+	if err != nil {
+		return "", nil, err
+	}
+	functionQuery := goqu.Dialect(b.Dialect).From(tableDef.FunctionExpression())
+	sql, args, err := query.SelectRow(true).With(withTable.GetTable(), functionQuery).ToSQL()
+	b.Logger.Debug("created sql mutation query", "query", sql, "args", args, "error", err)
+	return sql, args, err
+}
+
 // Query generates an SQL read query based on graphql ast.
 func (b Builder) Query(field builders.Field) (string, []any, error) {
 	var (
@@ -179,9 +220,11 @@ func (b Builder) Query(field builders.Field) (string, []any, error) {
 	)
 	if strings.HasSuffix(field.Name, "Aggregate") && strings.HasPrefix(field.Name, "_") {
 		// alias in root level
-		query, err = b.buildAggregate(getAggregateTableName(b.Schema, field.Field), field, true)
+		query, err = b.buildAggregate(getFunctionTableName(b.Schema, getAggregatedField(field.Field), field.Arguments), field, true)
+	} else if isTablePerType(field) {
+		query, err = b.buildUnionQuery(field, b.Schema.GetPossibleTypes(field.TypeDefinition))
 	} else {
-		query, err = b.buildQuery(getTableNameFromField(b.Schema, field.Definition), field)
+		query, err = b.buildQuery(getFunctionTableName(b.Schema, field.Definition, field.Arguments), field)
 	}
 	if err != nil {
 		return "", nil, err
@@ -207,7 +250,16 @@ func (b Builder) buildUpdate(tableDef tableDefinition, field builders.Field) (*g
 	// Substitute KV from GraphQL input into case conversion expected in database
 	newRecord := make(map[string]any)
 	for k, v := range kv[0] {
-		newRecord[b.CaseConverter(k)] = v
+		if f := jsonInputField(tableDef.objType, k); f != nil {
+			col := b.CaseConverter(schema.GetJSONDirective(f).Column)
+			value, err := jsonUpdateValue(goqu.C(col), f, v)
+			if err != nil {
+				return nil, err
+			}
+			newRecord[col] = value
+			continue
+		}
+		newRecord[b.CaseConverter(k)] = inputValue(tableDef.objType, k, v)
 	}
 	table := tableDef.TableExpression().As(tableAlias)
 	q := goqu.Dialect(b.Dialect).Update(table).Set(newRecord).Prepared(true).Returning(goqu.Star())
@@ -234,13 +286,66 @@ func (b Builder) buildInsert(tableDef tableDefinition, kv []map[string]any) (*go
 	for i, record := range kv {
 		newRecord := make(map[string]any)
 		for k, v := range record {
-			newRecord[b.CaseConverter(k)] = v
+			// typed JSON fields are written to their @json column
+			if f := jsonInputField(tableDef.objType, k); f != nil {
+				value, err := jsonInputValue(v)
+				if err != nil {
+					return nil, err
+				}
+				newRecord[b.CaseConverter(schema.GetJSONDirective(f).Column)] = value
+				continue
+			}
+			newRecord[b.CaseConverter(k)] = inputValue(tableDef.objType, k, v)
 		}
 		kv[i] = newRecord
 	}
 	return goqu.Dialect(b.Dialect).Insert(table).Rows(kv).Prepared(true).Returning(goqu.Star()), nil
 }
 
+// getInterfaceInputValues converts interface create inputs, each holding exactly one implementation, into rows of the
+// interface's table with the typename column set to the lower cased implementation name.
+// Columns missing from some rows are set to their DEFAULT value, so all rows share the same columns.
+func (b Builder) getInterfaceInputValues(def *ast.Definition, kv []map[string]any) ([]map[string]any, error) {
+	typeName := typeNameKey(def)
+	implementations := make(map[string]*ast.Definition)
+	for _, impl := range b.Schema.GetPossibleTypes(def) {
+		implementations[strcase.ToLowerCamel(impl.Name)] = impl
+	}
+	rows := make([]map[string]any, 0, len(kv))
+	columns := make(map[string]struct{})
+	for _, input := range kv {
+		if len(input) != 1 {
+			return nil, fmt.Errorf("expected exactly one implementation of %s per input, got %d", def.Name, len(input))
+		}
+		for k, v := range input {
+			impl, ok := implementations[k]
+			if !ok {
+				return nil, fmt.Errorf("unknown implementation %s of %s", k, def.Name)
+			}
+			values, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("expected input map for %s got %T", k, v)
+			}
+			row := make(map[string]any, len(values)+1)
+			for col, value := range values {
+				row[col] = value
+				columns[col] = struct{}{}
+			}
+			row[typeName] = strings.ToLower(impl.Name)
+			columns[typeName] = struct{}{}
+			rows = append(rows, row)
+		}
+	}
+	for _, row := range rows {
+		for col := range columns {
+			if _, ok := row[col]; !ok {
+				row[col] = goqu.Default()
+			}
+		}
+	}
+	return rows, nil
+}
+
 func (b Builder) buildDelete(tableDef tableDefinition, field builders.Field) (*goqu.DeleteDataset, error) {
 	b.Logger.Debug("building delete", "tableDefinition", tableDef.name)
 	q := goqu.Dialect(b.Dialect).Delete(tableDef.TableExpression()).Returning(goqu.Star())
@@ -261,7 +366,7 @@ func (b Builder) buildDelete(tableDef tableDefinition, field builders.Field) (*g
 func (b Builder) buildQuery(tableDef tableDefinition, field builders.Field) (*queryHelper, error) {
 	b.Logger.Debug("building query", map[string]any{"tableDefinition": tableDef.name})
 	tableAlias := b.TableNameGenerator.Generate(6)
-	table := tableDef.TableExpression().As(tableAlias)
+	table := tableDef.FromExpression(tableAlias)
 	query := queryHelper{goqu.From(table), table, tableAlias, nil, b.Dialect}
 
 	fieldsAdded := make(map[string]struct{})
@@ -286,15 +391,24 @@ func (b Builder) buildQuery(tableDef tableDefinition, field builders.Field) (*qu
 		switch childField.FieldType {
 		case builders.TypeScalar:
 			b.Logger.Debug("adding field", "tableDefinition", tableDef.name, "fieldName", childField.Name)
+			if d := computedField(field.TypeDefinition, childField.Name); d != nil {
+				query.selects = append(query.selects, column{table: query.alias, name: childField.Name, alias: childField.Name, expression: computedExpression(query.table, d).As(childField.Name)})
+				continue
+			}
+			if childField.TypeDefinition != nil && schema.IsGeoType(childField.TypeDefinition.Name) {
+				geoExp := geoSelect(query.table.Col(b.CaseConverter(childField.Name))).As(childField.Name)
+				query.selects = append(query.selects, column{table: query.alias, name: childField.Name, alias: childField.Name, expression: geoExp})
+				continue
+			}
 			query.selects = append(query.selects, column{table: query.alias, name: b.CaseConverter(childField.Name), alias: childField.Name})
 		case builders.TypeRelation:
 			b.Logger.Debug("adding relation field", "tableDefinition", tableDef.name, "fieldName", childField.Name)
 			if err := b.buildRelation(&query, childField); err != nil {
-				return nil, fmt.Errorf("failed to build relation for %s", childField.Name)
+				return nil, fmt.Errorf("failed to build relation for %s: %w", childField.Name, err)
 			}
 		case builders.TypeAggregate:
 			if err := b.buildRelationAggregate(&query, childField); err != nil {
-				return nil, fmt.Errorf("failed to build relation for %s", childField.Name)
+				return nil, fmt.Errorf("failed to build relation for %s: %w", childField.Name, err)
 			}
 		case builders.TypeJson:
 			b.Logger.Debug("adding JSON field", "tableDefinition", tableDef.name, "fieldName", childField.Name)
@@ -303,14 +417,20 @@ func (b Builder) buildQuery(tableDef tableDefinition, field builders.Field) (*qu
 			}
 		default:
 			b.Logger.Error("unknown field type", "tableDefinition", tableDef.name, "fieldName", childField.Name, "fieldType", childField.FieldType)
-			panic("unknown field type")
+			return nil, builders.InternalErrorf("unknown field type %v of %s", childField.FieldType, childField.Name)
 		}
 	}
 	b.buildPagination(&query, field)
-	b.buildOrdering(&query, field)
+	if err := b.buildOrdering(&query, field); err != nil {
+		return nil, err
+	}
 	if err := b.buildFiltering(&query, field); err != nil {
 		return nil, err
 	}
+	if err := b.buildSearch(&query, field); err != nil {
+		return nil, err
+	}
+	b.buildKeyFilter(&query, field)
 
 	return &query, nil
 }
@@ -336,41 +456,149 @@ func (b Builder) buildPayloadQuery(withTable exp.IdentifierExpression, baseQuery
 	return goqu.Select(cols...).With(withTable.GetTable(), baseQuery), nil
 }
 
-func (b Builder) buildAggregateGroupBy(table exp.AliasedExpression, groupBy []string) ([]any, []any) {
+func (b Builder) buildAggregateGroupBy(query *queryHelper, objType *ast.Definition, groupBy []string) ([]any, []any) {
 	groupByCols := make([]any, 0, len(groupBy))
 	groupByResult := make([]any, 0, 2*len(groupBy))
+	// ONE_TO_ONE relations joined to group by their fields, by relation name
+	relTables := make(map[string]exp.AliasedExpression)
 	for _, k := range groupBy {
-		groupByCols = append(groupByCols, table.Col(b.CaseConverter(k)))
-		groupByResult = append(groupByResult, goqu.L(fmt.Sprintf("'%s'", b.CaseConverter(k))), table.Col(b.CaseConverter(k)))
+		var col any = query.table.Col(b.CaseConverter(k))
+		if objType != nil {
+			// time bucket values i.e. CREATED_AT_DAY group by the column truncated to the bucket
+			if f, bucket, ok := schema.GetTimeBucket(objType, k); ok {
+				col = timeBucket(query.table.Col(b.CaseConverter(f.Name)), bucket)
+			} else if r, ok := schema.GetRelationGroupBy(b.Schema, objType, k); ok {
+				relTable, ok := relTables[r.Relation.Name]
+				if !ok {
+					relTable = b.joinOneToOne(query, r.Relation)
+					relTables[r.Relation.Name] = relTable
+				}
+				col = relTable.Col(b.CaseConverter(r.Field.Name))
+				if d := schema.GetComputedDirective(r.Field); d != nil {
+					col = computedExpression(relTable, d)
+				}
+			} else if j, ok := schema.GetJSONGroupBy(b.Schema, objType, k); ok {
+				col = jsonPathValue(query.table.Col(b.CaseConverter(j.Column())), j.Keys(), j.Leaf().Type.Name())
+			} else if f, ok := schema.GetComputedGroupBy(objType, k); ok {
+				col = computedExpression(query.table, schema.GetComputedDirective(f))
+			}
+		}
+		groupByCols = append(groupByCols, col)
+		groupByResult = append(groupByResult, goqu.L(fmt.Sprintf("'%s'", b.CaseConverter(k))), col)
 	}
 	return groupByCols, groupByResult
+}
+
+// joinOneToOne left joins the table of a ONE_TO_ONE relation field to the query, returning the joined table
+func (b Builder) joinOneToOne(query *queryHelper, relField *ast.FieldDefinition) exp.AliasedExpression {
+	rel := schema.GetRelationDirective(relField)
+	alias := b.TableNameGenerator.Generate(6)
+	relTable := getTableNameFromField(b.Schema, relField).TableExpression().As(alias)
+	query.SelectDataset = query.LeftJoin(relTable, goqu.On(buildCrossCondition(query.alias, rel.Fields, alias, rel.References)))
+	return relTable
+}
+
+// buildAggregateHaving filters the aggregated groups, i.e. having: {count: {gt: 1}, sum: {price: {lt: 100}}}
+func (b Builder) buildAggregateHaving(query *queryHelper, field builders.Field) error {
+	having, ok := field.Arguments["having"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	var havingExps []exp.Expression
+	for _, aggName := range sortedKeys(having) {
+		if aggName == "count" {
+			exps, err := buildExpressionOperations(goqu.L("?", goqu.COUNT(goqu.L("1"))), having[aggName])
+			if err != nil {
+				return err
+			}
+			havingExps = append(havingExps, exps...)
+			continue
+		}
+		aggFunc, ok := b.Aggregates[aggName]
+		if !ok {
+			return builders.ValidationErrorf("having on aggregator %s not supported", aggName)
+		}
+		fields, ok := having[aggName].(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected having of %s to be a map got %T", aggName, having[aggName])
+		}
+		for _, f := range sortedKeys(fields) {
+			exps, err := buildExpressionOperations(goqu.L("?", aggFunc(query.table.Col(b.CaseConverter(f)), nil)), fields[f])
+			if err != nil {
+				return err
+			}
+			havingExps = append(havingExps, exps...)
+		}
+	}
+	if len(havingExps) > 0 {
+		query.SelectDataset = query.Having(havingExps...)
+	}
+	return nil
+}
 
+// buildAggregateOrdering orders the aggregated groups, i.e. orderBy: [{count: DESC}, {max: {price: ASC}}]
+func (b Builder) buildAggregateOrdering(query *queryHelper, field builders.Field) error {
+	orderBy, ok := field.Arguments["orderBy"]
+	if !ok {
+		return nil
+	}
+	orderings, ok := orderBy.([]any)
+	if !ok {
+		orderings = []any{orderBy}
+	}
+	for _, o := range orderings {
+		ordering, ok := o.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected aggregate ordering to be a map got %T", o)
+		}
+		for _, aggName := range sortedKeys(ordering) {
+			if aggName == "count" {
+				b.appendOrdering(query, goqu.L("?", goqu.COUNT(goqu.L("1"))), builders.OrderingTypes(cast.ToString(ordering[aggName])))
+				continue
+			}
+			aggFunc, ok := b.Aggregates[aggName]
+			if !ok {
+				return builders.ValidationErrorf("ordering by aggregator %s not supported", aggName)
+			}
+			fields, ok := ordering[aggName].(map[string]any)
+			if !ok {
+				return fmt.Errorf("expected ordering of %s to be a map got %T", aggName, ordering[aggName])
+			}
+			for _, f := range sortedKeys(fields) {
+				b.appendOrdering(query, goqu.L("?", aggFunc(query.table.Col(b.CaseConverter(f)), nil)), builders.OrderingTypes(cast.ToString(fields[f])))
+			}
+		}
+	}
+	return nil
 }
 
 func (b Builder) buildAggregate(tableDef tableDefinition, field builders.Field, aliasAggregates bool) (*queryHelper, error) {
 	b.Logger.Debug("building aggregate", "tableDefinition", tableDef.name)
 	tableAlias := b.TableNameGenerator.Generate(6)
-	table := tableDef.TableExpression().As(tableAlias)
+	table := tableDef.FromExpression(tableAlias)
 	query := &queryHelper{goqu.Dialect(b.Dialect).From(table), table, tableAlias, nil, b.Dialect}
 	var fieldExp exp.Expression
+	var groupByResult []any
+	if groupBy, ok := field.Arguments["groupBy"]; ok && groupBy != nil {
+		groupByMap, err := cast.ToStringSliceE(groupBy)
+		if err != nil {
+			return nil, fmt.Errorf("expected group by map got %T", groupBy)
+		}
+		var groupByCols []any
+		groupByCols, groupByResult = b.buildAggregateGroupBy(query, tableDef.objType, groupByMap)
+		query.SelectDataset = query.GroupBy(groupByCols...)
+	}
 	for _, f := range field.Selections {
 		switch f.Name {
 		case "group":
-			groupBy, ok := field.Arguments["groupBy"]
-			if !ok {
+			if groupByResult == nil {
 				continue
 			}
-			groupByMap, err := cast.ToStringSliceE(groupBy)
-			if err != nil {
-				return nil, fmt.Errorf("expected group by map got %T", groupBy)
-			}
-			groupByCols, groupByResult := b.buildAggregateGroupBy(table, groupByMap)
 			fieldExp = goqu.Func("json_build_object", groupByResult...)
 			if aliasAggregates {
 				fieldExp = fieldExp.(exp.Aliaseable).As("group")
 			}
 			query.selects = append(query.selects, column{table: query.alias, name: f.Name, expression: fieldExp})
-			query.SelectDataset = query.GroupBy(groupByCols...)
 		case "count":
 			b.Logger.Debug("adding field", "tableDefinition", tableDef.name, "fieldName", f.Name)
 			fieldExp = goqu.COUNT(goqu.L("1"))
@@ -388,37 +616,88 @@ func (b Builder) buildAggregate(tableDef tableDefinition, field builders.Field,
 					aggExp = aggExp.(exp.Aliaseable).As(f.Name)
 				}
 				query.selects = append(query.selects, column{table: query.alias, name: f.Name, expression: aggExp})
+			} else if agg, ok := b.Aggregates[f.Name]; ok {
+				aggExp := buildAggregateObject(table, f.Selections, agg, f.Arguments, b.CaseConverter)
+				if aliasAggregates {
+					aggExp = aggExp.(exp.Aliaseable).As(f.Name)
+				}
+				query.selects = append(query.selects, column{table: query.alias, name: f.Name, expression: aggExp})
 			} else {
-				return nil, fmt.Errorf("aggrgator %s not supported", f.Name)
+				return nil, builders.ValidationErrorf("aggregator %s not supported", f.Name)
 			}
 		}
 	}
 	if err := b.buildFiltering(query, field); err != nil {
 		return nil, err
 	}
+	if err := b.buildAggregateHaving(query, field); err != nil {
+		return nil, err
+	}
+	if err := b.buildAggregateOrdering(query, field); err != nil {
+		return nil, err
+	}
+	b.buildPagination(query, field)
 	return query, nil
 }
 
-func (b Builder) buildOrdering(query *queryHelper, field builders.Field) {
+func (b Builder) buildOrdering(query *queryHelper, field builders.Field) error {
 	orderBy, ok := field.Arguments["orderBy"]
 	if !ok {
-		return
+		return nil
+	}
+	orderFields, err := builders.CollectOrdering(orderBy, field.OrderingDefinition(b.Schema))
+	if err != nil {
+		return err
 	}
-	orderFields, _ := builders.CollectOrdering(orderBy)
 
 	for _, o := range orderFields {
 		b.Logger.Debug("adding ordering", "tableDefinition", query.TableName(), "field", o.Key, "orderType", o.Type)
-		switch o.Type {
-		case builders.OrderingTypesAsc:
-			query.SelectDataset = query.OrderAppend(goqu.C(b.CaseConverter(o.Key)).Asc().NullsLast())
-		case builders.OrderingTypesAscNull:
-			query.SelectDataset = query.OrderAppend(goqu.C(b.CaseConverter(o.Key)).Asc().NullsFirst())
-		case builders.OrderingTypesDesc:
-			query.SelectDataset = query.OrderAppend(goqu.C(b.CaseConverter(o.Key)).Desc().NullsLast())
-		case builders.OrderingTypesDescNull:
-			query.SelectDataset = query.OrderAppend(goqu.C(b.CaseConverter(o.Key)).Desc().NullsFirst())
+		var orderExp exp.Orderable = goqu.C(b.CaseConverter(o.Key))
+		if o.Value != nil {
+			// order by distance of the column from the given point
+			orderExp = goqu.L("? <-> ?", goqu.C(b.CaseConverter(o.Key)), geoFromGeoJSON(o.Value))
+		}
+		if len(o.Path) > 0 {
+			// order by a field nested in a typed JSON field, i.e. {attributes: {price: DESC}}
+			j, ok := schema.GetJSONPath(b.Schema, field.TypeDefinition, o.Key, o.Path)
+			if !ok {
+				continue
+			}
+			orderExp = jsonPathValue(goqu.C(b.CaseConverter(j.Column())), j.Keys(), j.Leaf().Type.Name())
+		}
+		if d := computedField(field.TypeDefinition, o.Key); d != nil {
+			orderExp = computedExpression(query.table, d)
+		}
+		if o.Key == schema.SearchRankOrderingName {
+			if orderExp = b.buildSearchRank(query, field); orderExp == nil {
+				continue
+			}
 		}
+		b.appendOrdering(query, orderExp, o.Type)
 	}
+	return nil
+}
+
+// appendOrdering appends the expression to the query's ORDER BY with the ordering type's direction and nulls order
+func (b Builder) appendOrdering(query *queryHelper, orderExp exp.Orderable, orderType builders.OrderingTypes) {
+	if ordered := orderExpression(orderExp, orderType); ordered != nil {
+		query.SelectDataset = query.OrderAppend(ordered)
+	}
+}
+
+// orderExpression orders the expression with the ordering type's direction and nulls order
+func orderExpression(orderExp exp.Orderable, orderType builders.OrderingTypes) exp.OrderedExpression {
+	switch orderType {
+	case builders.OrderingTypesAsc:
+		return orderExp.Asc().NullsLast()
+	case builders.OrderingTypesAscNull:
+		return orderExp.Asc().NullsFirst()
+	case builders.OrderingTypesDesc:
+		return orderExp.Desc().NullsLast()
+	case builders.OrderingTypesDescNull:
+		return orderExp.Desc().NullsFirst()
+	}
+	return nil
 }
 
 func (b Builder) buildPagination(query *queryHelper, field builders.Field) {
@@ -446,6 +725,39 @@ func (b Builder) buildFiltering(query *queryHelper, field builders.Field) error
 	return nil
 }
 
+// buildKeyFilter restricts the query to rows matching the field's key filter, used for batched relation resolving
+func (b Builder) buildKeyFilter(query *queryHelper, field builders.Field) {
+	if field.KeyFilter == nil {
+		return
+	}
+	columns := field.KeyFilter.Columns
+	if len(field.KeyFilter.Fields) > 0 {
+		columns = make([]string, 0, len(field.KeyFilter.Fields))
+		for _, f := range field.KeyFilter.Fields {
+			columns = append(columns, b.CaseConverter(f))
+		}
+	}
+	b.Logger.Debug("adding key filter", "tableDefinition", query.TableName(), "columns", columns)
+	if len(columns) == 1 {
+		values := make([]any, 0, len(field.KeyFilter.Values))
+		for _, v := range field.KeyFilter.Values {
+			values = append(values, v[0])
+		}
+		query.SelectDataset = query.Where(query.table.Col(columns[0]).In(values))
+		return
+	}
+	// composite keys are matched as an OR of each key tuple
+	tuples := exp.NewExpressionList(exp.OrType)
+	for _, v := range field.KeyFilter.Values {
+		tuple := exp.NewExpressionList(exp.AndType)
+		for i, c := range columns {
+			tuple = tuple.Append(query.table.Col(c).Eq(v[i]))
+		}
+		tuples = tuples.Append(tuple)
+	}
+	query.SelectDataset = query.Where(tuples)
+}
+
 func (b Builder) buildFilterLogicalExp(table tableHelper, astDefinition *ast.Definition, filtersList []any, logicalType exp.ExpressionListType) (goqu.Expression, error) {
 	expBuilder := exp.NewExpressionList(logicalType)
 	for _, filterValue := range filtersList {
@@ -491,6 +803,12 @@ func (b Builder) buildFilterExp(table tableHelper, astDefinition *ast.Definition
 				return nil, err
 			}
 			expBuilder = expBuilder.Append(logicalExp)
+		case k == schema.SearchFilterFieldName:
+			searchExp, err := b.buildSearchExp(table.table, astDefinition, v)
+			if err != nil {
+				return nil, err
+			}
+			expBuilder = expBuilder.Append(searchExp)
 		case k == string(builders.LogicalOperatorNot):
 			filterExp, err := b.buildFilterExp(table, astDefinition, filters)
 			if err != nil {
@@ -505,7 +823,11 @@ func (b Builder) buildFilterExp(table tableHelper, astDefinition *ast.Definition
 			fid := filterInputDef.Fields.ForName(k)
 			if fid.Directives.ForName("isInterfaceFilter") != nil {
 				// add type filter + interface filter
-				expBuilder = expBuilder.Append(b.buildInterfaceFilter(table, astDefinition, b.Schema.Types[strcase.ToCamel(k)], kv))
+				interfaceExp, err := b.buildInterfaceFilter(table, astDefinition, b.Schema.Types[strcase.ToCamel(k)], kv)
+				if err != nil {
+					return nil, err
+				}
+				expBuilder = expBuilder.Append(interfaceExp)
 				continue
 			}
 			ffd := astDefinition.Fields.ForName(k)
@@ -525,7 +847,7 @@ func (b Builder) buildFilterExp(table tableHelper, astDefinition *ast.Definition
 			// Create a Builder for relational filter
 			rel := schema.GetRelationDirective(ffd)
 			if rel == nil {
-				return nil, fmt.Errorf("missing directive relation")
+				return nil, builders.InternalErrorf("field %s is missing the @relation directive", k)
 			}
 			fq, err := b.buildFilterQuery(table, b.Schema.Types[ffd.Type.Name()], *rel, kv)
 			if err != nil {
@@ -545,9 +867,23 @@ func (b Builder) buildFilterExp(table tableHelper, astDefinition *ast.Definition
 			// sort keys for consistency in query building
 			slices.Sort(opKeys)
 
+			if d := computedField(astDefinition, k); d != nil {
+				exps, err := buildExpressionOperations(computedExpression(table.table, d), opMap)
+				if err != nil {
+					return nil, fmt.Errorf("building filter for computed field %s: %w", k, err)
+				}
+				expBuilder = expBuilder.Append(goqu.And(exps...))
+				continue
+			}
+			var scalarName string
+			var list bool
+			if fd := astDefinition.Fields.ForName(k); fd != nil {
+				scalarName = fd.Type.Name()
+				list = schema.IsListType(fd.Type)
+			}
 			for _, op := range opKeys {
 				value := opMap[op]
-				opExp, err := b.buildOperation(table.table, k, op, value)
+				opExp, err := b.buildOperation(table.table, k, scalarName, list, op, value)
 				if err != nil {
 					return nil, err
 				}
@@ -622,8 +958,15 @@ func (b Builder) buildJsonField(query *queryHelper, jsonField builders.Field) er
 	// Get the JSONB column reference from the parent table
 	jsonCol := query.table.Col(b.CaseConverter(jsonColumnName))
 
-	// Build expression using PostgreSQL -> operator and jsonb_build_object for JSON field extraction
-	jsonObjExpr, err := BuildJsonFieldObject(jsonCol, jsonField.Selections, b.Dialect)
+	// Build expression using PostgreSQL -> operator and jsonb_build_object for JSON field extraction,
+	// arrays of objects are projected element-wise
+	var jsonObjExpr exp.Expression
+	var err error
+	if isJsonArray(jsonField) {
+		jsonObjExpr, err = BuildJsonFieldArray(jsonCol, jsonField, b.Dialect)
+	} else {
+		jsonObjExpr, err = BuildJsonFieldObject(jsonCol, jsonField.Selections, b.Dialect)
+	}
 	if err != nil {
 		return fmt.Errorf("building JSON object for field %s: %w", jsonField.Name, err)
 	}
@@ -699,7 +1042,7 @@ func (b Builder) buildFilterQuery(parentTable tableHelper, rf *ast.Definition, r
 		fq.SelectDataset = fq.InnerJoin(parentTable.table.Aliased().(exp.Aliaseable).As(b.TableNameGenerator.Generate(6)),
 			goqu.On(buildJoinCondition(parentTable.alias, rel.Fields, fq.alias, rel.References)...))
 	default:
-		panic("unknown relation type")
+		return nil, builders.InternalErrorf("unknown relation type %s", rel.RelType)
 	}
 	expBuilder, err := b.buildFilterExp(fq.Table(), rf, filters)
 	if err != nil {
@@ -709,20 +1052,37 @@ func (b Builder) buildFilterQuery(parentTable tableHelper, rf *ast.Definition, r
 	return fq, nil
 }
 
-func (b Builder) buildInterfaceFilter(table tableHelper, parentDef, definition *ast.Definition, kv map[string]any) goqu.Expression {
+func (b Builder) buildInterfaceFilter(table tableHelper, parentDef, definition *ast.Definition, kv map[string]any) (goqu.Expression, error) {
+	// table per type, the filter only applies to the implementor's own table
+	if table.typeName != "" {
+		if table.typeName != definition.Name {
+			return goqu.L("false"), nil
+		}
+		return b.buildFilterExp(table, definition, kv)
+	}
 	d := parentDef.Directives.ForName("typename").Arguments.ForName("name").Value.Raw
 	filterExp, err := b.buildFilterExp(table, definition, kv)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return goqu.And(filterExp, table.table.Col(d).Eq(strings.ToLower(definition.Name)))
+	return goqu.And(filterExp, table.table.Col(d).Eq(strings.ToLower(definition.Name))), nil
 }
 
-// buildOperation creates a goqu.Expression SQL operator
-func (b Builder) buildOperation(table exp.AliasedExpression, fieldName, operatorName string, value any) (goqu.Expression, error) {
+// buildOperation creates a goqu.Expression SQL operator, operators of list fields and of the field's scalar type take
+// precedence
+func (b Builder) buildOperation(table exp.AliasedExpression, fieldName, scalarName string, list bool, operatorName string, value any) (goqu.Expression, error) {
+	if opFunc, ok := b.ListOperators[operatorName]; ok && list {
+		return opFunc(table, b.CaseConverter(fieldName), value), nil
+	}
+	if sc, ok := schema.GetScalar(b.Schema, scalarName); ok && sc.Cast != "" {
+		value = castOperatorValue(operatorName, value, sc.Cast)
+	}
+	if opFunc, ok := b.ScalarOperators[scalarName][operatorName]; ok {
+		return opFunc(table, b.CaseConverter(fieldName), value), nil
+	}
 	opFunc, ok := b.Operators[operatorName]
 	if !ok {
-		return nil, fmt.Errorf("key operator %s not supported", operatorName)
+		return nil, builders.ValidationErrorf("key operator %s not supported", operatorName)
 	}
 	return opFunc(table, b.CaseConverter(fieldName), value), nil
 }
@@ -31,6 +31,9 @@ type TestBuilderCase struct {
 	ExpectedArguments []interface{}
 	ExpectedSQL       string
 	CustomOperators   map[string]builders.Operator
+	CustomAggregates  map[string]builders.AggregateFunc
+	// Aggregates are the custom aggregates the schema is augmented with
+	Aggregates []schema.Aggregate
 }
 
 type TestTableNameGenerator struct {
@@ -118,6 +121,181 @@ func TestBuilder_Query(t *testing.T) {
 			ExpectedSQL:       `SELECT "sq0"."type" AS "type", "sq0"."id" AS "id", "sq0"."name" AS "name", "sq0"."breed" AS "breed" FROM "app"."animals" AS "sq0" LIMIT $1`,
 			ExpectedArguments: []interface{}{int64(100)},
 		},
+		{
+			Name:       "query_cross_dialect_relation",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				users {
+					name
+					activities {
+						name
+					}
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name", "sq0"."id" AS "id" FROM "app"."users" AS "sq0" LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:       "query_union_of_tables",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				feed {
+					... on Post {
+						id
+						name
+					}
+					... on Comment {
+						id
+						body
+					}
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq2"."typename" AS "typename", "sq2"."id" AS "id", "sq2"."name" AS "name", "sq2"."body" AS "body" FROM (SELECT 'post' AS "typename", "sq0"."id" AS "id", "sq0"."name" AS "name", (SELECT "sq1"."body" AS "body" FROM "comments" AS "sq1" WHERE false) AS "body" FROM "posts" AS "sq0" UNION ALL (SELECT 'comment' AS "typename", "sq1"."id" AS "id", (SELECT "sq0"."name" AS "name" FROM "posts" AS "sq0" WHERE false) AS "name", "sq1"."body" AS "body" FROM "comments" AS "sq1")) AS "sq2" LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:       "query_union_of_tables_with_relation",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				feed(limit: 10, offset: 5) {
+					... on Comment {
+						body
+						post {
+							name
+						}
+					}
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq3"."typename" AS "typename", "sq3"."body" AS "body", "sq3"."post" AS "post" FROM (SELECT 'post' AS "typename", (SELECT "sq1"."body" AS "body" FROM "comments" AS "sq1" WHERE false) AS "body", NULL::jsonb AS "post" FROM "posts" AS "sq0" UNION ALL (SELECT 'comment' AS "typename", "sq1"."body" AS "body", "sq2"."post" AS "post" FROM "comments" AS "sq1" LEFT JOIN LATERAL (SELECT jsonb_build_object('name', "sq2"."name") AS "post" FROM "posts" AS "sq2" WHERE sq1.post_id = sq2.id) AS "sq2" ON true)) AS "sq3" LIMIT $1 OFFSET $2`,
+			ExpectedArguments: []interface{}{int64(10), int64(5)},
+		},
+		{
+			Name:       "query_interface_table_per_type",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				vehicles {
+					id
+					... on Car {
+						doors
+					}
+					... on Bike {
+						gears
+					}
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq2"."typename" AS "typename", "sq2"."id" AS "id", "sq2"."gears" AS "gears", "sq2"."doors" AS "doors" FROM (SELECT 'bike' AS "typename", "sq0"."id" AS "id", "sq0"."gears" AS "gears", (SELECT "sq1"."doors" AS "doors" FROM "cars" AS "sq1" WHERE false) AS "doors" FROM "bikes" AS "sq0" UNION ALL (SELECT 'car' AS "typename", "sq1"."id" AS "id", (SELECT "sq0"."gears" AS "gears" FROM "bikes" AS "sq0" WHERE false) AS "gears", "sq1"."doors" AS "doors" FROM "cars" AS "sq1")) AS "sq2" LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:       "query_interface_table_per_type_filter_order",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				vehicles(filter: {name: {eq: "fast"}, car: {doors: {gt: 2}}}, orderBy: {name: DESC}, limit: 5) {
+					id
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq2"."typename" AS "typename", "sq2"."id" AS "id" FROM (SELECT 'bike' AS "typename", "sq0"."id" AS "id", "sq0"."name" AS "name" FROM "bikes" AS "sq0" WHERE (false AND ("sq0"."name" = $1)) UNION ALL (SELECT 'car' AS "typename", "sq1"."id" AS "id", "sq1"."name" AS "name" FROM "cars" AS "sq1" WHERE (("sq1"."doors" > $2) AND ("sq1"."name" = $3)))) AS "sq2" ORDER BY "name" DESC NULLS LAST LIMIT $4`,
+			ExpectedArguments: []interface{}{"fast", int64(2), "fast", int64(5)},
+		},
+		{
+			Name:       "query_interface_table_per_type_order_by_member_field",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				vehicles(orderBy: {topSpeed: DESC}) {
+					id
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq2"."typename" AS "typename", "sq2"."id" AS "id" FROM (SELECT 'bike' AS "typename", "sq0"."id" AS "id", (SELECT "sq1"."top_speed" AS "topSpeed" FROM "cars" AS "sq1" WHERE false) AS "topSpeed" FROM "bikes" AS "sq0" UNION ALL (SELECT 'car' AS "typename", "sq1"."id" AS "id", "sq1"."top_speed" AS "topSpeed" FROM "cars" AS "sq1")) AS "sq2" ORDER BY "topSpeed" DESC NULLS LAST LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:       "query_interface_table_per_type_order_by_selected_member_field",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				vehicles(orderBy: {topSpeed: ASC}) {
+					id
+					... on Car {
+						topSpeed
+					}
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq2"."typename" AS "typename", "sq2"."id" AS "id", "sq2"."topSpeed" AS "topSpeed" FROM (SELECT 'bike' AS "typename", "sq0"."id" AS "id", (SELECT "sq1"."top_speed" AS "topSpeed" FROM "cars" AS "sq1" WHERE false) AS "topSpeed" FROM "bikes" AS "sq0" UNION ALL (SELECT 'car' AS "typename", "sq1"."id" AS "id", "sq1"."top_speed" AS "topSpeed" FROM "cars" AS "sq1")) AS "sq2" ORDER BY "topSpeed" ASC NULLS LAST LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:       "query_union_single_table",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				pets {
+					... on Dog {
+						name
+						breed
+					}
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq0"."type" AS "type", "sq0"."name" AS "name", "sq0"."breed" AS "breed" FROM "app"."animals" AS "sq0" LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:       "query_skip_select_fields",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				users {
+					name
+					displayName
+					followerCount
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name", "sq0"."id" AS "id" FROM "app"."users" AS "sq0" LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:       "query_skip_select_key_already_selected",
+			SchemaFile: "testdata/schema_simple.graphql",
+			GraphQLQuery: `query {
+				users {
+					id
+					followerCount
+				}
+			}`,
+			ExpectedSQL:       `SELECT "sq0"."id" AS "id" FROM "app"."users" AS "sq0" LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:              "query_search",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { posts(search: "fast graphql") { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "posts" AS "sq0" WHERE to_tsvector($1::regconfig, coalesce("sq0"."name", '')) @@ websearch_to_tsquery($2::regconfig, $3) LIMIT $4`,
+			ExpectedArguments: []interface{}{"english", "english", "fast graphql", int64(100)},
+		},
+		{
+			Name:              "query_search_filter",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { posts(filter: {_search: "graphql", id: {gt: 1}}) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "posts" AS "sq0" WHERE (to_tsvector($1::regconfig, coalesce("sq0"."name", '')) @@ websearch_to_tsquery($2::regconfig, $3) AND ("sq0"."id" > $4)) LIMIT $5`,
+			ExpectedArguments: []interface{}{"english", "english", "graphql", int64(1), int64(100)},
+		},
+		{
+			Name:              "query_search_order_by_rank",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { posts(search: "graphql", orderBy: {_rank: DESC}) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "posts" AS "sq0" WHERE to_tsvector($1::regconfig, coalesce("sq0"."name", '')) @@ websearch_to_tsquery($2::regconfig, $3) ORDER BY ts_rank(to_tsvector($4::regconfig, coalesce("sq0"."name", '')), websearch_to_tsquery($5::regconfig, $6)) DESC NULLS LAST LIMIT $7`,
+			ExpectedArguments: []interface{}{"english", "english", "graphql", "english", "english", "graphql", int64(100)},
+		},
+		{
+			Name:              "query_order_by_rank_without_search",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { posts(orderBy: {_rank: DESC}) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "posts" AS "sq0" LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:              "relation_search",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { users { name posts(search: "graphql") { name } } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name", "sq1"."posts" AS "posts" FROM "app"."users" AS "sq0" LEFT JOIN LATERAL (SELECT COALESCE(jsonb_agg(jsonb_build_object('name', "sq1"."name")), '[]'::jsonb) AS "posts" FROM "posts" AS "sq1" WHERE (to_tsvector($1::regconfig, coalesce("sq1"."name", '')) @@ websearch_to_tsquery($2::regconfig, $3) AND sq0.id = sq1.user_id) LIMIT $4) AS "sq1" ON true LIMIT $5`,
+			ExpectedArguments: []interface{}{"english", "english", "graphql", int64(100), int64(100)},
+		},
 	}
 	_ = os.Chdir("/testdata")
 	for _, testCase := range testCases {
@@ -131,6 +309,52 @@ func TestBuilder_Query(t *testing.T) {
 
 }
 
+func TestBuilder_KeyFilter(t *testing.T) {
+	testCases := []struct {
+		TestBuilderCase
+		KeyFilter *builders.KeyFilter
+	}{
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "single_column_key",
+				SchemaFile:        "testdata/schema_simple.graphql",
+				GraphQLQuery:      `query { posts { name } }`,
+				ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "posts" AS "sq0" WHERE ("sq0"."user_id" IN ($1, $2)) LIMIT $3`,
+				ExpectedArguments: []interface{}{int64(1), int64(2), int64(100)},
+			},
+			KeyFilter: &builders.KeyFilter{Columns: []string{"user_id"}, Values: [][]any{{int64(1)}, {int64(2)}}},
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "composite_key",
+				SchemaFile:        "testdata/schema_simple.graphql",
+				GraphQLQuery:      `query { posts { name } }`,
+				ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "posts" AS "sq0" WHERE ((("sq0"."user_id" = $1) AND ("sq0"."name" = $2)) OR (("sq0"."user_id" = $3) AND ("sq0"."name" = $4))) LIMIT $5`,
+				ExpectedArguments: []interface{}{int64(1), "a", int64(2), "b", int64(100)},
+			},
+			KeyFilter: &builders.KeyFilter{Columns: []string{"user_id", "name"}, Values: [][]any{{int64(1), "a"}, {int64(2), "b"}}},
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "field_key",
+				SchemaFile:        "testdata/schema_simple.graphql",
+				GraphQLQuery:      `query { posts { name } }`,
+				ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "posts" AS "sq0" WHERE ("sq0"."user_id" IN ($1, $2)) LIMIT $3`,
+				ExpectedArguments: []interface{}{int64(1), int64(2), int64(100)},
+			},
+			KeyFilter: &builders.KeyFilter{Fields: []string{"userId"}, Values: [][]any{{int64(1)}, {int64(2)}}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase.TestBuilderCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				f.KeyFilter = testCase.KeyFilter
+				return b.Query(f)
+			})
+		})
+	}
+}
+
 func TestBuilder_Insert(t *testing.T) {
 	testCases := []TestBuilderCase{
 		{
@@ -147,6 +371,13 @@ func TestBuilder_Insert(t *testing.T) {
 			ExpectedSQL:       `WITH create_posts AS (INSERT INTO "posts" AS "sq0" ("id", "name") VALUES (111, 'Ron'), (133, 'Ron') RETURNING *) SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('name', "sq1"."name", 'id', "sq1"."id")), '[]'::jsonb) AS "posts" FROM "create_posts" AS "sq1") AS "posts", (SELECT COUNT(*) AS "rows_affected" FROM "create_posts")`,
 			ExpectedArguments: []interface{}{},
 		},
+		{
+			Name:              "interface_insert",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `mutation { createAnimals(inputs: [{cat: {id: "1", name: "Tom", color: "grey"}}, {dog: {id: "2", name: "Rex", breed: "lab"}}]) { rows_affected animals { id name } } }`,
+			ExpectedSQL:       `WITH create_animals AS (INSERT INTO "app"."animals" AS "sq0" ("breed", "color", "id", "name", "type") VALUES (DEFAULT, 'grey', '1', 'Tom', 'cat'), ('lab', DEFAULT, '2', 'Rex', 'dog') RETURNING *) SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('type', "sq1"."type", 'id', "sq1"."id", 'name', "sq1"."name")), '[]'::jsonb) AS "animals" FROM "create_animals" AS "sq1") AS "animals", (SELECT COUNT(*) AS "rows_affected" FROM "create_animals")`,
+			ExpectedArguments: []interface{}{},
+		},
 	}
 	_ = os.Chdir("/testdata")
 	for _, testCase := range testCases {
@@ -160,6 +391,63 @@ func TestBuilder_Insert(t *testing.T) {
 
 }
 
+func TestBuilder_JsonMutations(t *testing.T) {
+	testCases := []struct {
+		TestBuilderCase
+		update bool
+	}{
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "insert_json",
+				SchemaFile:        "testdata/schema_json.graphql",
+				GraphQLQuery:      `mutation { createProducts(inputs: {id: 1, name: "chair", attributes: {color: "red", details: {model: "x"}}, reviews: [{rating: 5}]}) { rows_affected } }`,
+				ExpectedSQL:       "WITH create_products AS (INSERT INTO \"app\".\"products\" AS \"sq0\" (\"attributes\", \"id\", \"name\", \"reviews\") VALUES ('{\"color\":\"red\",\"details\":{\"model\":\"x\"}}'::jsonb, 1, 'chair', '[{\"rating\":5}]'::jsonb) RETURNING *) SELECT (SELECT COUNT(*) AS \"rows_affected\" FROM \"create_products\")",
+				ExpectedArguments: []interface{}{},
+			},
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "update_json_set",
+				SchemaFile:        "testdata/schema_json.graphql",
+				GraphQLQuery:      `mutation { updateProducts(input: {attributes: {set: {color: "blue"}}}) { rows_affected } }`,
+				ExpectedSQL:       "WITH update_products AS (UPDATE \"app\".\"products\" AS \"sq0\" SET \"attributes\"='{\"color\":\"blue\"}'::jsonb RETURNING *) SELECT (SELECT COUNT(*) AS \"rows_affected\" FROM \"update_products\")",
+				ExpectedArguments: []interface{}{},
+			},
+			update: true,
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "update_json_merge",
+				SchemaFile:        "testdata/schema_json.graphql",
+				GraphQLQuery:      `mutation { updateProducts(input: {attributes: {merge: {size: 3, details: {warranty: {years: 2}}, color: null}}}) { rows_affected } }`,
+				ExpectedSQL:       "WITH update_products AS (UPDATE \"app\".\"products\" AS \"sq0\" SET \"attributes\"=COALESCE(\"attributes\", '{}'::jsonb) || jsonb_build_object('color', 'null'::jsonb, 'details', COALESCE(\"attributes\"->'details', '{}'::jsonb) || jsonb_build_object('warranty', COALESCE(\"attributes\"->'details'->'warranty', '{}'::jsonb) || jsonb_build_object('years', '2'::jsonb)), 'size', '3'::jsonb) RETURNING *) SELECT (SELECT COUNT(*) AS \"rows_affected\" FROM \"update_products\")",
+				ExpectedArguments: []interface{}{},
+			},
+			update: true,
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "update_json_list",
+				SchemaFile:        "testdata/schema_json.graphql",
+				GraphQLQuery:      `mutation { updateProducts(input: {reviews: [{rating: 4, body: "ok"}]}) { rows_affected } }`,
+				ExpectedSQL:       "WITH update_products AS (UPDATE \"app\".\"products\" AS \"sq0\" SET \"reviews\"='[{\"body\":\"ok\",\"rating\":4}]'::jsonb RETURNING *) SELECT (SELECT COUNT(*) AS \"rows_affected\" FROM \"update_products\")",
+				ExpectedArguments: []interface{}{},
+			},
+			update: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase.TestBuilderCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				if testCase.update {
+					return b.Update(f)
+				}
+				return b.Create(f)
+			})
+		})
+	}
+}
+
 func TestBuilder_Delete(t *testing.T) {
 	testCases := []TestBuilderCase{
 		{
@@ -176,6 +464,13 @@ func TestBuilder_Delete(t *testing.T) {
 			ExpectedSQL:       `WITH delete_posts AS (DELETE FROM "posts" WHERE ("posts"."id" = 1) RETURNING *) SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('name', "sq0"."name", 'id', "sq0"."id")), '[]'::jsonb) AS "posts" FROM "delete_posts" AS "sq0") AS "posts", (SELECT COUNT(*) AS "rows_affected" FROM "delete_posts")`,
 			ExpectedArguments: []interface{}{},
 		},
+		{
+			Name:              "interface_delete_with_type_filter",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `mutation { deleteAnimals(filter: {cat: {color: {eq: "grey"}}}) { rows_affected animals { id name } } }`,
+			ExpectedSQL:       `WITH delete_animals AS (DELETE FROM "app"."animals" WHERE (("animals"."color" = 'grey') AND ("animals"."type" = 'cat')) RETURNING *) SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('type', "sq0"."type", 'id', "sq0"."id", 'name', "sq0"."name")), '[]'::jsonb) AS "animals" FROM "delete_animals" AS "sq0") AS "animals", (SELECT COUNT(*) AS "rows_affected" FROM "delete_animals")`,
+			ExpectedArguments: []interface{}{},
+		},
 	}
 	_ = os.Chdir("/testdata")
 	for _, testCase := range testCases {
@@ -205,6 +500,13 @@ func TestBuilder_Update(t *testing.T) {
 			ExpectedSQL:       `WITH update_posts AS (UPDATE "posts" AS "sq0" SET "name"='newPost' WHERE ("sq0"."id" = 1) RETURNING *) SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('name', "sq1"."name", 'id', "sq1"."id")), '[]'::jsonb) AS "posts" FROM "update_posts" AS "sq1") AS "posts", (SELECT COUNT(*) AS "rows_affected" FROM "update_posts")`,
 			ExpectedArguments: []interface{}{},
 		},
+		{
+			Name:              "interface_update_with_type_filter",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `mutation { updateAnimals(input: {name: "Rex"}, filter: {dog: {breed: {eq: "lab"}}}) { rows_affected animals { id name } } }`,
+			ExpectedSQL:       `WITH update_animals AS (UPDATE "app"."animals" AS "sq0" SET "name"='Rex' WHERE (("sq0"."breed" = 'lab') AND ("sq0"."type" = 'dog')) RETURNING *) SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('type', "sq1"."type", 'id', "sq1"."id", 'name', "sq1"."name")), '[]'::jsonb) AS "animals" FROM "update_animals" AS "sq1") AS "animals", (SELECT COUNT(*) AS "rows_affected" FROM "update_animals")`,
+			ExpectedArguments: []interface{}{},
+		},
 	}
 	_ = os.Chdir("/testdata")
 	for _, testCase := range testCases {
@@ -218,6 +520,438 @@ func TestBuilder_Update(t *testing.T) {
 
 }
 
+func TestBuilder_Geo(t *testing.T) {
+	testCases := []struct {
+		TestBuilderCase
+		caller func(b sql.Builder, f builders.Field) (string, []interface{}, error)
+	}{
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "select_geometry",
+				SchemaFile:        "testdata/schema_geo.graphql",
+				GraphQLQuery:      `query { places { name location visits { location } } }`,
+				ExpectedSQL:       `SELECT "sq0"."name" AS "name", ST_AsGeoJSON("sq0"."location")::jsonb AS "location", "sq1"."visits" AS "visits" FROM "app"."places" AS "sq0" LEFT JOIN LATERAL (SELECT COALESCE(jsonb_agg(jsonb_build_object('location', ST_AsGeoJSON("sq1"."location")::jsonb)), '[]'::jsonb) AS "visits" FROM "app"."visits" AS "sq1" WHERE sq0.id = sq1.place_id LIMIT $1) AS "sq1" ON true LIMIT $2`,
+				ExpectedArguments: []interface{}{int64(100), int64(100)},
+			},
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "filter_geometry",
+				SchemaFile:        "testdata/schema_geo.graphql",
+				GraphQLQuery:      `query { places(filter: {area: {containsPoint: {type: "Point", coordinates: [1, 2]}}, position: {dWithin: {point: {type: "Point", coordinates: [1, 2]}, distance: 500}}}) { name } }`,
+				ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "app"."places" AS "sq0" WHERE (ST_Contains("sq0"."area", ST_GeomFromGeoJSON($1)) AND ST_DWithin("sq0"."position", ST_GeomFromGeoJSON($2), $3)) LIMIT $4`,
+				ExpectedArguments: []interface{}{`{"coordinates":[1,2],"type":"Point"}`, `{"coordinates":[1,2],"type":"Point"}`, float64(500), int64(100)},
+			},
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "order_by_distance",
+				SchemaFile:        "testdata/schema_geo.graphql",
+				GraphQLQuery:      `query { places(orderBy: [{location: {from: {type: "Point", coordinates: [1, 2]}}}, {name: DESC}]) { name } }`,
+				ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "app"."places" AS "sq0" ORDER BY "location" <-> ST_GeomFromGeoJSON($1) ASC NULLS LAST, "name" DESC NULLS LAST LIMIT $2`,
+				ExpectedArguments: []interface{}{`{"coordinates":[1,2],"type":"Point"}`, int64(100)},
+			},
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "insert_geometry",
+				SchemaFile:        "testdata/schema_geo.graphql",
+				GraphQLQuery:      `mutation { createPlaces(inputs: {id: 1, location: {type: "Point", coordinates: [1, 2]}}) { rows_affected places { location } } }`,
+				ExpectedSQL:       `WITH create_places AS (INSERT INTO "app"."places" AS "sq0" ("id", "location") VALUES (1, ST_GeomFromGeoJSON('{"coordinates":[1,2],"type":"Point"}')) RETURNING *) SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('location', ST_AsGeoJSON("sq1"."location")::jsonb)), '[]'::jsonb) AS "places" FROM "create_places" AS "sq1") AS "places", (SELECT COUNT(*) AS "rows_affected" FROM "create_places")`,
+				ExpectedArguments: []interface{}{},
+			},
+			caller: func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Create(f)
+			},
+		},
+		{
+			TestBuilderCase: TestBuilderCase{
+				Name:              "update_geometry",
+				SchemaFile:        "testdata/schema_geo.graphql",
+				GraphQLQuery:      `mutation { updatePlaces(input: {location: {type: "Point", coordinates: [1, 2]}}, filter: {id: {eq: 1}}) { rows_affected } }`,
+				ExpectedSQL:       `WITH update_places AS (UPDATE "app"."places" AS "sq0" SET "location"=ST_GeomFromGeoJSON('{"coordinates":[1,2],"type":"Point"}') WHERE ("sq0"."id" = 1) RETURNING *) SELECT (SELECT COUNT(*) AS "rows_affected" FROM "update_places")`,
+				ExpectedArguments: []interface{}{},
+			},
+			caller: func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Update(f)
+			},
+		},
+	}
+	_ = os.Chdir("/testdata")
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			caller := testCase.caller
+			if caller == nil {
+				caller = func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+					return b.Query(f)
+				}
+			}
+			builderTester(t, testCase.TestBuilderCase, caller)
+		})
+	}
+}
+
+func TestBuilder_CustomAggregate(t *testing.T) {
+	aggregates := []schema.Aggregate{
+		{Name: "countDistinct", Kind: "Int"},
+		{
+			Name:               "percentile",
+			AllowedScalarTypes: []string{"Int", "Float"},
+			Kind:               "Float",
+			Arguments:          ast.ArgumentDefinitionList{{Name: "p", Type: ast.NonNullNamedType("Float", nil)}},
+		},
+	}
+	customAggregates := map[string]builders.AggregateFunc{
+		"countDistinct": func(col exp.IdentifierExpression, _ map[string]any) goqu.Expression {
+			return goqu.L("COUNT(DISTINCT ?)", col)
+		},
+		"percentile": func(col exp.IdentifierExpression, args map[string]any) goqu.Expression {
+			return goqu.L("percentile_cont(?) WITHIN GROUP (ORDER BY ?)", args["p"], col)
+		},
+	}
+	testCases := []TestBuilderCase{
+		{
+			Name:              "custom_aggregate",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { _postsAggregate { countDistinct { name } } }`,
+			ExpectedSQL:       `SELECT json_build_object('name', COUNT(DISTINCT "sq0"."name")) AS "countDistinct" FROM "posts" AS "sq0"`,
+			ExpectedArguments: []interface{}{},
+			CustomAggregates:  customAggregates,
+			Aggregates:        aggregates,
+		},
+		{
+			Name:              "custom_aggregate_arguments",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { _postsAggregate { percentile(p: 0.5) { id } } }`,
+			ExpectedSQL:       `SELECT json_build_object('id', percentile_cont($1) WITHIN GROUP (ORDER BY "sq0"."id")) AS "percentile" FROM "posts" AS "sq0"`,
+			ExpectedArguments: []interface{}{0.5},
+			CustomAggregates:  customAggregates,
+			Aggregates:        aggregates,
+		},
+		{
+			Name:              "custom_aggregate_having",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { _postsAggregate(groupBy: [ID], having: {countDistinct: {name: {gt: 1}}}) { count } }`,
+			ExpectedSQL:       `SELECT COUNT(1) AS "count" FROM "posts" AS "sq0" GROUP BY "sq0"."id" HAVING (COUNT(DISTINCT "sq0"."name") > $1)`,
+			ExpectedArguments: []interface{}{int64(1)},
+			CustomAggregates:  customAggregates,
+			Aggregates:        aggregates,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
+func TestBuilder_AggregatorOperators(t *testing.T) {
+	builderTester(t, TestBuilderCase{
+		Name:              "aggregator_operator",
+		SchemaFile:        "testdata/schema_simple.graphql",
+		GraphQLQuery:      `query { _postsAggregate { max { id } } }`,
+		ExpectedSQL:       `SELECT json_build_object('id', 1) AS "max" FROM "posts" AS "sq0"`,
+		ExpectedArguments: []interface{}{},
+	}, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+		// deprecated aggregator operators take precedence over the aggregates of the same name
+		b.AggregatorOperators["max"] = func(_ exp.AliasedExpression, _ []builders.Field) (goqu.Expression, error) {
+			return goqu.L("json_build_object('id', 1)"), nil
+		}
+		return b.Query(f)
+	})
+}
+
+func TestBuilder_Aggregate(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "aggregate_group_by",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { _postsAggregate(groupBy: [NAME]) { group count } }`,
+			ExpectedSQL:       `SELECT json_build_object('name', "sq0"."name") AS "group", COUNT(1) AS "count" FROM "posts" AS "sq0" GROUP BY "sq0"."name"`,
+			ExpectedArguments: []interface{}{},
+		},
+		{
+			Name:              "aggregate_group_by_without_group_selection",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { _postsAggregate(groupBy: [NAME]) { count } }`,
+			ExpectedSQL:       `SELECT COUNT(1) AS "count" FROM "posts" AS "sq0" GROUP BY "sq0"."name"`,
+			ExpectedArguments: []interface{}{},
+		},
+		{
+			Name:              "aggregate_group_by_one_to_one_relation",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { _postsAggregate(groupBy: [USER__NAME, USER__ID, NAME]) { group count } }`,
+			ExpectedSQL:       `SELECT json_build_object('user__name', "sq1"."name", 'user__id', "sq1"."id", 'name', "sq0"."name") AS "group", COUNT(1) AS "count" FROM "posts" AS "sq0" LEFT JOIN "app"."users" AS "sq1" ON sq0.user_id = sq1.id GROUP BY "sq1"."name", "sq1"."id", "sq0"."name"`,
+			ExpectedArguments: []interface{}{},
+		},
+		{
+			Name:              "aggregate_having",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { _postsAggregate(groupBy: [NAME], having: {count: {gt: 1}, max: {id: {lte: 10}}}) { group count } }`,
+			ExpectedSQL:       `SELECT json_build_object('name', "sq0"."name") AS "group", COUNT(1) AS "count" FROM "posts" AS "sq0" GROUP BY "sq0"."name" HAVING ((COUNT(1) > $1) AND (MAX("sq0"."id") <= $2))`,
+			ExpectedArguments: []interface{}{int64(1), int64(10)},
+		},
+		{
+			Name:              "aggregate_order_by_and_pagination",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { _postsAggregate(groupBy: [NAME], orderBy: [{count: DESC}, {sum: {id: ASC}}], limit: 10, offset: 20) { group count sum { id } } }`,
+			ExpectedSQL:       `SELECT json_build_object('name', "sq0"."name") AS "group", COUNT(1) AS "count", json_build_object('id', SUM("sq0"."id")) AS "sum" FROM "posts" AS "sq0" GROUP BY "sq0"."name" ORDER BY COUNT(1) DESC NULLS LAST, SUM("sq0"."id") ASC NULLS LAST LIMIT $1 OFFSET $2`,
+			ExpectedArguments: []interface{}{int64(10), int64(20)},
+		},
+		{
+			Name:              "relation_aggregate_having_and_order_by",
+			SchemaFile:        "testdata/schema_simple.graphql",
+			GraphQLQuery:      `query { users { name _postsAggregate(groupBy: [NAME], having: {count: {gte: 2}}, orderBy: {count: DESC}, limit: 3) { group count } } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name", "sq1"."_posts_aggregate" AS "_posts_aggregate" FROM "app"."users" AS "sq0" LEFT JOIN LATERAL (SELECT jsonb_agg("sq1"."_posts_aggregate") AS "_posts_aggregate" FROM (SELECT jsonb_build_object('group', json_build_object('name', "sq1"."name"), 'count', COUNT(1)) AS "_posts_aggregate" FROM "posts" AS "sq1" WHERE sq0.id = sq1.user_id GROUP BY "sq1"."name" HAVING (COUNT(1) >= $1) ORDER BY COUNT(1) DESC NULLS LAST LIMIT $2) AS "sq1") AS "sq1" ON true LIMIT $3`,
+			ExpectedArguments: []interface{}{int64(2), int64(3), int64(100)},
+		},
+	}
+	_ = os.Chdir("/testdata")
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
+func TestBuilder_Temporal(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "filter_between",
+			SchemaFile:        "testdata/schema_temporal.graphql",
+			GraphQLQuery:      `query { events(filter: {day: {between: {from: "2024-01-01", to: "2024-01-31"}}, startsAt: {gte: "09:00:00"}}) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "app"."events" AS "sq0" WHERE (("sq0"."day" BETWEEN $1 AND $2) AND ("sq0"."starts_at" >= $3)) LIMIT $4`,
+			ExpectedArguments: []interface{}{"2024-01-01", "2024-01-31", "09:00:00", int64(100)},
+		},
+		{
+			Name:              "filter_relative_interval",
+			SchemaFile:        "testdata/schema_temporal.graphql",
+			GraphQLQuery:      `query { events(filter: {createdAt: {within: "7 days"}, day: {olderThan: "1 year"}}) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "app"."events" AS "sq0" WHERE (("sq0"."created_at" BETWEEN now() - $1::interval AND now()) AND ("sq0"."day" < now() - $2::interval)) LIMIT $3`,
+			ExpectedArguments: []interface{}{"7 days", "1 year", int64(100)},
+		},
+		{
+			Name:              "filter_day_of_week_and_year",
+			SchemaFile:        "testdata/schema_temporal.graphql",
+			GraphQLQuery:      `query { events(filter: {createdAt: {dayOfWeek: 1, year: 2024}}) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "app"."events" AS "sq0" WHERE ((EXTRACT(ISODOW FROM "sq0"."created_at") = $1) AND (EXTRACT(YEAR FROM "sq0"."created_at") = $2)) LIMIT $3`,
+			ExpectedArguments: []interface{}{int64(1), int64(2024), int64(100)},
+		},
+		{
+			Name:              "filter_interval",
+			SchemaFile:        "testdata/schema_temporal.graphql",
+			GraphQLQuery:      `query { events(filter: {duration: {gt: "1 hour"}}) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "app"."events" AS "sq0" WHERE ("sq0"."duration" > $1) LIMIT $2`,
+			ExpectedArguments: []interface{}{"1 hour", int64(100)},
+		},
+		{
+			Name:              "aggregate_group_by_time_bucket",
+			SchemaFile:        "testdata/schema_temporal.graphql",
+			GraphQLQuery:      `query { _eventsAggregate(groupBy: [CREATED_AT_DAY, NAME]) { group count } }`,
+			ExpectedSQL:       `SELECT json_build_object('created_at_day', date_trunc('day', "sq0"."created_at"), 'name', "sq0"."name") AS "group", COUNT(1) AS "count" FROM "app"."events" AS "sq0" GROUP BY date_trunc('day', "sq0"."created_at"), "sq0"."name"`,
+			ExpectedArguments: []interface{}{},
+		},
+		{
+			Name:              "aggregate_group_by_date_month",
+			SchemaFile:        "testdata/schema_temporal.graphql",
+			GraphQLQuery:      `query { _eventsAggregate(groupBy: [DAY_MONTH]) { group max { day createdAt } } }`,
+			ExpectedSQL:       `SELECT json_build_object('day_month', date_trunc('month', "sq0"."day")) AS "group", json_build_object('day', MAX("sq0"."day"), 'createdAt', MAX("sq0"."created_at")) AS "max" FROM "app"."events" AS "sq0" GROUP BY date_trunc('month', "sq0"."day")`,
+			ExpectedArguments: []interface{}{},
+		},
+	}
+	_ = os.Chdir("/testdata")
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
+func TestBuilder_Scalars(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "filter_cast",
+			SchemaFile:        "testdata/schema_scalars.graphql",
+			GraphQLQuery:      `query { accounts(filter: {id: {in: ["5f0c7d0e-1f8a-4b1e-9c4a-2d6f3e7a8b9c"]}, balance: {gt: "10.5"}}) { name } }`,
+			ExpectedSQL:       "SELECT \"sq0\".\"name\" AS \"name\" FROM \"app\".\"accounts\" AS \"sq0\" WHERE ((\"sq0\".\"balance\" > CAST($1 AS numeric)) AND (\"sq0\".\"id\" IN (CAST($2 AS uuid)))) LIMIT $3",
+			ExpectedArguments: []interface{}{"10.5", "5f0c7d0e-1f8a-4b1e-9c4a-2d6f3e7a8b9c", int64(100)},
+		},
+		{
+			Name:              "order",
+			SchemaFile:        "testdata/schema_scalars.graphql",
+			GraphQLQuery:      `query { accounts(orderBy: [{balance: DESC}]) { name } }`,
+			ExpectedSQL:       "SELECT \"sq0\".\"name\" AS \"name\" FROM \"app\".\"accounts\" AS \"sq0\" ORDER BY \"balance\" DESC NULLS LAST LIMIT $1",
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:              "aggregate",
+			SchemaFile:        "testdata/schema_scalars.graphql",
+			GraphQLQuery:      `query { _accountsAggregate { sum { balance } max { balance } } }`,
+			ExpectedSQL:       "SELECT json_build_object('balance', SUM(\"sq0\".\"balance\")) AS \"sum\", json_build_object('balance', MAX(\"sq0\".\"balance\")) AS \"max\" FROM \"app\".\"accounts\" AS \"sq0\"",
+			ExpectedArguments: []interface{}{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
+func TestBuilder_Enum(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "filter_in",
+			SchemaFile:        "testdata/schema_enum.graphql",
+			GraphQLQuery:      `query { tickets(filter: {status: {in: [OPEN, CLOSED]}}) { name } }`,
+			ExpectedSQL:       "SELECT \"sq0\".\"name\" AS \"name\" FROM \"app\".\"tickets\" AS \"sq0\" WHERE (\"sq0\".\"status\" IN ($1, $2)) LIMIT $3",
+			ExpectedArguments: []interface{}{"OPEN", "CLOSED", int64(100)},
+		},
+		{
+			Name:              "filter_not_in",
+			SchemaFile:        "testdata/schema_enum.graphql",
+			GraphQLQuery:      `query { tickets(filter: {status: {notIn: [CLOSED]}}) { name } }`,
+			ExpectedSQL:       "SELECT \"sq0\".\"name\" AS \"name\" FROM \"app\".\"tickets\" AS \"sq0\" WHERE (\"sq0\".\"status\" NOT IN ($1)) LIMIT $2",
+			ExpectedArguments: []interface{}{"CLOSED", int64(100)},
+		},
+		{
+			Name:              "filter_list_contains_overlap",
+			SchemaFile:        "testdata/schema_enum.graphql",
+			GraphQLQuery:      `query { tickets(filter: {labels: {contains: [BUG], overlap: [BUG, FEATURE]}}) { name } }`,
+			ExpectedSQL:       "SELECT \"sq0\".\"name\" AS \"name\" FROM \"app\".\"tickets\" AS \"sq0\" WHERE (\"sq0\".\"labels\" @> $1 AND \"sq0\".\"labels\" && $2) LIMIT $3",
+			ExpectedArguments: []interface{}{`{"BUG"}`, `{"BUG","FEATURE"}`, int64(100)},
+		},
+		{
+			Name:              "filter_list_eq",
+			SchemaFile:        "testdata/schema_enum.graphql",
+			GraphQLQuery:      `query { tickets(filter: {labels: {eq: [BUG]}}) { name } }`,
+			ExpectedSQL:       "SELECT \"sq0\".\"name\" AS \"name\" FROM \"app\".\"tickets\" AS \"sq0\" WHERE \"sq0\".\"labels\" = $1 LIMIT $2",
+			ExpectedArguments: []interface{}{`{"BUG"}`, int64(100)},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
+func TestBuilder_Computed(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "select",
+			SchemaFile:        "testdata/schema_computed.graphql",
+			GraphQLQuery:      `query { people { id fullName ageInDays } }`,
+			ExpectedSQL:       `SELECT "sq0"."id" AS "id", (SELECT (first_name || ' ' || last_name) FROM (SELECT "sq0".*) AS "computed") AS "fullName", app.age_in_days("sq0") AS "ageInDays" FROM "app"."people" AS "sq0" LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:              "filter_and_order",
+			SchemaFile:        "testdata/schema_computed.graphql",
+			GraphQLQuery:      `query { people(filter: {fullName: {eq: "John Doe"}, ageInDays: {gt: 365}}, orderBy: [{ageInDays: DESC}]) { fullName } }`,
+			ExpectedSQL:       `SELECT (SELECT (first_name || ' ' || last_name) FROM (SELECT "sq0".*) AS "computed") AS "fullName" FROM "app"."people" AS "sq0" WHERE ((app.age_in_days("sq0") > $1) AND ((SELECT (first_name || ' ' || last_name) FROM (SELECT "sq0".*) AS "computed") = $2)) ORDER BY app.age_in_days("sq0") DESC NULLS LAST LIMIT $3`,
+			ExpectedArguments: []interface{}{int64(365), "John Doe", int64(100)},
+		},
+		{
+			Name:              "aggregate_group_by",
+			SchemaFile:        "testdata/schema_computed.graphql",
+			GraphQLQuery:      `query { _peopleAggregate(groupBy: [FULL_NAME]) { group count } }`,
+			ExpectedSQL:       `SELECT json_build_object('full_name', (SELECT (first_name || ' ' || last_name) FROM (SELECT "sq0".*) AS "computed")) AS "group", COUNT(1) AS "count" FROM "app"."people" AS "sq0" GROUP BY (SELECT (first_name || ' ' || last_name) FROM (SELECT "sq0".*) AS "computed")`,
+			ExpectedArguments: []interface{}{},
+		},
+		{
+			Name:              "aggregate_group_by_relation",
+			SchemaFile:        "testdata/schema_computed.graphql",
+			GraphQLQuery:      `query { _peopleAggregate(groupBy: [FULL_NAME, MANAGER__FULL_NAME]) { group count } }`,
+			ExpectedSQL:       `SELECT json_build_object('full_name', (SELECT (first_name || ' ' || last_name) FROM (SELECT "sq0".*) AS "computed"), 'manager__full_name', (SELECT (first_name || ' ' || last_name) FROM (SELECT "sq1".*) AS "computed")) AS "group", COUNT(1) AS "count" FROM "app"."people" AS "sq0" LEFT JOIN "app"."people" AS "sq1" ON sq0.manager_id = sq1.id GROUP BY (SELECT (first_name || ' ' || last_name) FROM (SELECT "sq0".*) AS "computed"), (SELECT (first_name || ' ' || last_name) FROM (SELECT "sq1".*) AS "computed")`,
+			ExpectedArguments: []interface{}{},
+		},
+		{
+			Name:              "relation_filter",
+			SchemaFile:        "testdata/schema_computed.graphql",
+			GraphQLQuery:      `query { people(filter: {manager: {fullName: {eq: "Jane Doe"}}}) { id } }`,
+			ExpectedSQL:       `SELECT "sq0"."id" AS "id" FROM "app"."people" AS "sq0" WHERE exists((SELECT 1 FROM "app"."people" AS "sq1" INNER JOIN "app"."people" AS "sq2" ON (sq0.manager_id = sq1.id AND sq0.manager_id = sq2.id) WHERE ((SELECT (first_name || ' ' || last_name) FROM (SELECT "sq1".*) AS "computed") = $1))) LIMIT $2`,
+			ExpectedArguments: []interface{}{"Jane Doe", int64(100)},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
+func TestBuilder_Function(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "query",
+			SchemaFile:        "testdata/schema_function.graphql",
+			GraphQLQuery:      `query { searchProducts(term: "chair", filter: {price: {lt: 100}}, orderBy: [{price: ASC}], limit: 10) { id name } }`,
+			ExpectedSQL:       `SELECT "sq0"."id" AS "id", "sq0"."name" AS "name" FROM app.search_products($1, $2) AS "sq0" WHERE ("sq0"."price" < $3) ORDER BY "price" ASC NULLS LAST LIMIT $4`,
+			ExpectedArguments: []interface{}{"chair", nil, int64(100), int64(10)},
+		},
+		{
+			Name:              "query_default_arguments",
+			SchemaFile:        "testdata/schema_function.graphql",
+			GraphQLQuery:      `query { searchProducts(term: "chair", minPrice: 10) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM app.search_products($1, $2) AS "sq0" LIMIT $3`,
+			ExpectedArguments: []interface{}{"chair", int64(10), int64(100)},
+		},
+		{
+			Name:              "aggregate",
+			SchemaFile:        "testdata/schema_function.graphql",
+			GraphQLQuery:      `query { _searchProductsAggregate(term: "chair", groupBy: [NAME]) { group count } }`,
+			ExpectedSQL:       `SELECT json_build_object('name', "sq0"."name") AS "group", COUNT(1) AS "count" FROM app.search_products($1, $2) AS "sq0" GROUP BY "sq0"."name"`,
+			ExpectedArguments: []interface{}{"chair", nil},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
+func TestBuilder_SQLMutation(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "single_row",
+			SchemaFile:        "testdata/schema_sql_mutation.graphql",
+			GraphQLQuery:      `mutation { approveOrder(id: 1, note: "ok") { id status user { name } } }`,
+			ExpectedSQL:       `WITH approve_order AS (SELECT * FROM app.approve_order($1, $2)) SELECT "sq0"."id" AS "id", "sq0"."status" AS "status", "sq1"."user" AS "user" FROM "approve_order" AS "sq0" LEFT JOIN LATERAL (SELECT jsonb_build_object('name', "sq1"."name") AS "user" FROM "app"."users" AS "sq1" WHERE sq0.user_id = sq1.id) AS "sq1" ON true`,
+			ExpectedArguments: []interface{}{int64(1), "ok"},
+		},
+		{
+			Name:              "rows",
+			SchemaFile:        "testdata/schema_sql_mutation.graphql",
+			GraphQLQuery:      `mutation { cancelOrders(ids: [1, 2]) { id status } }`,
+			ExpectedSQL:       `WITH cancel_orders AS (SELECT * FROM cancel_orders(ARRAY[$1, $2]::integer[])) SELECT "sq0"."id" AS "id", "sq0"."status" AS "status" FROM "cancel_orders" AS "sq0"`,
+			ExpectedArguments: []interface{}{int64(1), int64(2)},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.SQLMutation(f)
+			})
+		})
+	}
+}
+
 func TestBuilder_CustomOperator(t *testing.T) {
 	testCases := []TestBuilderCase{
 		{
@@ -401,6 +1135,74 @@ func TestBuilder_Capabilities(t *testing.T) {
 	assert.Equal(t, -1, caps.MaxRelationDepth)
 }
 
+func TestBuilder_Query_JsonArrays(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "typed_json_array",
+			SchemaFile:        "testdata/schema_json.graphql",
+			GraphQLQuery:      `query { products { name reviews { rating } } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name", (SELECT COALESCE(jsonb_agg(jsonb_build_object('rating', "elem0"->$1)), '[]'::jsonb) FROM (SELECT "elem0" FROM jsonb_array_elements("sq0"."reviews") AS "elem0" LIMIT $2) AS "elems0") AS "reviews" FROM "app"."products" AS "sq0" LIMIT $3`,
+			ExpectedArguments: []interface{}{"rating", int64(100), int64(100)},
+		},
+		{
+			Name:              "typed_json_array_arguments",
+			SchemaFile:        "testdata/schema_json.graphql",
+			GraphQLQuery:      `query { products { reviews(filter: {rating: {gte: 4}}, orderBy: [{rating: DESC}], limit: 3, offset: 1) { rating body } } }`,
+			ExpectedSQL:       `SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('rating', "elem0"->$1, 'body', "elem0"->$2) ORDER BY "elem0"->$3 DESC NULLS LAST), '[]'::jsonb) FROM (SELECT "elem0" FROM jsonb_array_elements("sq0"."reviews") AS "elem0" WHERE jsonb_path_exists("elem0", $4::jsonpath, $5::jsonb) ORDER BY "elem0"->$6 DESC NULLS LAST LIMIT $7 OFFSET $8) AS "elems0") AS "reviews" FROM "app"."products" AS "sq0" LIMIT $9`,
+			ExpectedArguments: []interface{}{"rating", "body", "rating", `$ ? (@.rating >= $v0)`, `{"v0":4}`, "rating", int64(3), int64(1), int64(100)},
+		},
+		{
+			Name:              "typed_json_nested_array",
+			SchemaFile:        "testdata/schema_json.graphql",
+			GraphQLQuery:      `query { products { attributes { color variants(filter: {stock: {gt: 0}}, orderBy: [{price: ASC}], limit: 5) { sku price } } } }`,
+			ExpectedSQL:       `SELECT jsonb_build_object('color', "sq0"."attributes"->$1, 'variants', (SELECT COALESCE(jsonb_agg(jsonb_build_object('sku', "elem1"->$2, 'price', "elem1"->$3) ORDER BY "elem1"->$4 ASC NULLS LAST), '[]'::jsonb) FROM (SELECT "elem1" FROM jsonb_array_elements("sq0"."attributes"->$5) AS "elem1" WHERE jsonb_path_exists("elem1", $6::jsonpath, $7::jsonb) ORDER BY "elem1"->$8 ASC NULLS LAST LIMIT $9) AS "elems1")) AS "attributes" FROM "app"."products" AS "sq0" LIMIT $10`,
+			ExpectedArguments: []interface{}{"color", "sku", "price", "price", "variants", `$ ? (@.stock > $v0)`, `{"v0":0}`, "price", int64(5), int64(100)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
+func TestBuilder_Query_JsonPaths(t *testing.T) {
+	testCases := []TestBuilderCase{
+		{
+			Name:              "order_by_json_path",
+			SchemaFile:        "testdata/schema_json.graphql",
+			GraphQLQuery:      `query { products(orderBy: {attributes: {size: DESC}}) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "app"."products" AS "sq0" ORDER BY ("attributes"->>'size')::numeric DESC NULLS LAST LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:              "order_by_nested_json_path",
+			SchemaFile:        "testdata/schema_json.graphql",
+			GraphQLQuery:      `query { products(orderBy: [{attributes: {details: {manufacturer: ASC}}}, {name: DESC}]) { name } }`,
+			ExpectedSQL:       `SELECT "sq0"."name" AS "name" FROM "app"."products" AS "sq0" ORDER BY "attributes"->'details'->>'manufacturer' ASC NULLS LAST, "name" DESC NULLS LAST LIMIT $1`,
+			ExpectedArguments: []interface{}{int64(100)},
+		},
+		{
+			Name:              "group_by_json_path",
+			SchemaFile:        "testdata/schema_json.graphql",
+			GraphQLQuery:      `query { _productsAggregate(groupBy: [ATTRIBUTES__COLOR, ATTRIBUTES__DETAILS__WARRANTY__YEARS]) { group count } }`,
+			ExpectedSQL:       `SELECT json_build_object('attributes__color', "sq0"."attributes"->>'color', 'attributes__details__warranty__years', ("sq0"."attributes"->'details'->'warranty'->>'years')::numeric) AS "group", COUNT(1) AS "count" FROM "app"."products" AS "sq0" GROUP BY "sq0"."attributes"->>'color', ("sq0"."attributes"->'details'->'warranty'->>'years')::numeric`,
+			ExpectedArguments: []interface{}{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			builderTester(t, testCase, func(b sql.Builder, f builders.Field) (string, []interface{}, error) {
+				return b.Query(f)
+			})
+		})
+	}
+}
+
 func TestBuilder_Query_JsonFiltering(t *testing.T) {
 	testCases := []TestBuilderCase{
 		{
@@ -686,7 +1488,7 @@ func builderTester(t *testing.T, testCase TestBuilderCase, caller func(b sql.Bui
 		BuiltIn: false,
 	})
 	require.Nil(t, err)
-	fgqlPlugin := schema.FastGqlPlugin{}
+	fgqlPlugin := schema.NewFastGQLPlugin("", "", false, schema.WithAggregates(testCase.Aggregates...))
 	src, err := fgqlPlugin.CreateAugmented(testSchema)
 	require.Nil(t, err)
 	augmentedSchema, err := gqlparser.LoadSchema(src...)
@@ -697,6 +1499,7 @@ func builderTester(t *testing.T, testCase TestBuilderCase, caller func(b sql.Bui
 		Logger:             nil,
 		TableNameGenerator: &TestTableNameGenerator{},
 		CustomOperators:    testCase.CustomOperators,
+		CustomAggregates:   testCase.CustomAggregates,
 	})
 	doc, err := parser.ParseQuery(&ast.Source{Input: testCase.GraphQLQuery})
 	require.Nil(t, err)
@@ -711,7 +1514,8 @@ func builderTester(t *testing.T, testCase TestBuilderCase, caller func(b sql.Bui
 		Doc:           doc,
 		Stats:         graphql.Stats{},
 	}
-	field := builders.CollectFromQuery(sel, augmentedSchema, opCtx, sel.ArgumentMap(nil))
+	field, err := builders.CollectFromQuery(sel, augmentedSchema, opCtx, sel.ArgumentMap(nil))
+	require.NoError(t, err)
 	query, args, err := caller(builder, field)
 	assert.Nil(t, err)
 	if testCase.ExpectedArguments == nil {
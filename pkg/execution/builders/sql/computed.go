@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/schema"
+)
+
+// computedExpression returns the SQL expression of a @computed field of the table, a function is called with the
+// table's row, i.e. app.full_name("sq0"), and an SQL expression is evaluated over the table's row only, i.e.
+// (SELECT (price * quantity) FROM (SELECT "sq0".*) AS "computed"), so its columns aren't ambiguous with the columns of
+// joined tables or of an enclosing query.
+func computedExpression(table exp.AliasedExpression, d *schema.ComputedDirective) exp.LiteralExpression {
+	if d.Function != "" {
+		return goqu.L(fmt.Sprintf("%s(?)", d.Function), table.GetAs())
+	}
+	return goqu.L(`(SELECT ? FROM (SELECT ?) AS "computed")`, goqu.L(fmt.Sprintf("(%s)", d.SQL)), table.GetAs().All())
+}
+
+// computedField returns the @computed directive of the object's field, if the field is computed
+func computedField(obj *ast.Definition, fieldName string) *schema.ComputedDirective {
+	if obj == nil {
+		return nil
+	}
+	f := obj.Fields.ForName(fieldName)
+	if f == nil {
+		return nil
+	}
+	return schema.GetComputedDirective(f)
+}
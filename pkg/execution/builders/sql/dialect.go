@@ -2,6 +2,7 @@ package sql
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
@@ -14,6 +15,8 @@ type Dialect interface {
 	JSONBuildObject(args ...any) exp.SQLFunctionExpression
 	// JSONAgg aggregates rows into a JSON array
 	JSONAgg(expr exp.Expression) exp.SQLFunctionExpression
+	// JSONAggOrdered aggregates rows into a JSON array ordered by the expressions
+	JSONAggOrdered(expr exp.Expression, orderBy ...exp.OrderedExpression) exp.Expression
 	// CoalesceJSON returns a fallback value if the expression is null
 	CoalesceJSON(expr exp.Expression, fallback string) exp.SQLFunctionExpression
 	// JSONPathExists JSON filtering methods, and checks if a JSONPath expression matches
@@ -31,6 +34,17 @@ func (PostgresDialect) JSONAgg(expr exp.Expression) exp.SQLFunctionExpression {
 	return goqu.Func("jsonb_agg", expr)
 }
 
+func (d PostgresDialect) JSONAggOrdered(expr exp.Expression, orderBy ...exp.OrderedExpression) exp.Expression {
+	if len(orderBy) == 0 {
+		return d.JSONAgg(expr)
+	}
+	args := []any{expr}
+	for _, o := range orderBy {
+		args = append(args, o)
+	}
+	return goqu.L("jsonb_agg(? ORDER BY "+strings.TrimSuffix(strings.Repeat("?, ", len(orderBy)), ", ")+")", args...)
+}
+
 func (PostgresDialect) CoalesceJSON(expr exp.Expression, fallback string) exp.SQLFunctionExpression {
 	return goqu.COALESCE(expr, goqu.L(fallback))
 }
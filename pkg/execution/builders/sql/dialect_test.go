@@ -46,6 +46,20 @@ func TestPostgresDialect_JSONAgg(t *testing.T) {
 	assert.Contains(t, sql, "jsonb_agg")
 }
 
+func TestPostgresDialect_JSONAggOrdered(t *testing.T) {
+	dialect := PostgresDialect{}
+
+	expr := dialect.JSONAggOrdered(goqu.I("data"), goqu.I("name").Asc(), goqu.I("id").Desc().NullsLast())
+	sql, _, err := goqu.Dialect("postgres").Select(expr).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT jsonb_agg("data" ORDER BY "name" ASC, "id" DESC NULLS LAST)`, sql)
+
+	expr = dialect.JSONAggOrdered(goqu.I("data"))
+	sql, _, err = goqu.Dialect("postgres").Select(expr).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT jsonb_agg("data")`, sql)
+}
+
 func TestPostgresDialect_CoalesceJSON(t *testing.T) {
 	dialect := PostgresDialect{}
 
@@ -8,12 +8,17 @@ import (
 	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/jinzhu/inflection"
 	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/schema"
 )
 
 type tableDefinition struct {
 	name    string
 	schema  string
 	objType *ast.Definition
+	// function is the set-returning function the rows are selected from instead of the table, called with args
+	function *schema.FunctionDirective
+	args     []any
 }
 
 func (t tableDefinition) TableExpression() exp.IdentifierExpression {
@@ -24,6 +29,23 @@ func (t tableDefinition) TableExpression() exp.IdentifierExpression {
 	return tbl
 }
 
+// FromExpression returns the aliased table, or the aliased call of the set-returning function if it's defined
+func (t tableDefinition) FromExpression(alias string) exp.AliasedExpression {
+	if t.function == nil {
+		return t.TableExpression().As(alias)
+	}
+	return t.FunctionExpression().As(goqu.T(alias))
+}
+
+// FunctionExpression returns the call of the table's function with its arguments
+func (t tableDefinition) FunctionExpression() exp.SQLFunctionExpression {
+	name := t.function.Name
+	if t.function.Schema != "" {
+		name = fmt.Sprintf("%s.%s", t.function.Schema, name)
+	}
+	return goqu.Func(name, t.args...)
+}
+
 func (t tableDefinition) String() string {
 	if t.schema != "" {
 		return fmt.Sprintf(`"%s"."%s"`, t.schema, t.name)
@@ -40,9 +62,74 @@ func getTableNameFromField(schema *ast.Schema, f *ast.FieldDefinition) tableDefi
 // getTableNameFromField returns the field's type tableDefinition name in the database, if no directive is defined, type name is presumed
 // as the tableDefinition's name
 func getAggregateTableName(schema *ast.Schema, field *ast.Field) tableDefinition {
+	return getTableNameFromField(schema, getAggregatedField(field))
+}
+
+// getAggregatedField returns the list field aggregated by the aggregate field, i.e. posts of _postsAggregate
+func getAggregatedField(field *ast.Field) *ast.FieldDefinition {
 	fieldName := strings.Split(field.Name, "Aggregate")[0][1:]
-	nonAggField := field.ObjectDefinition.Fields.ForName(fieldName)
-	return getTableNameFromField(schema, nonAggField)
+	return field.ObjectDefinition.Fields.ForName(fieldName)
+}
+
+// getFunctionTableName returns the tableDefinition of a field, selecting from the field's set-returning function
+// called with the argument values if the field has a @function directive
+func getFunctionTableName(s *ast.Schema, f *ast.FieldDefinition, args map[string]any) tableDefinition {
+	tableDef := getTableNameFromField(s, f)
+	d := schema.GetFunctionDirective(f)
+	if d == nil {
+		return tableDef
+	}
+	tableDef.function = d
+	tableDef.args = functionArgumentValues(s, f, args)
+	return tableDef
+}
+
+// getSQLMutationTableName returns the tableDefinition of a @sqlMutation field's type, with the field's function called
+// with the argument values
+func getSQLMutationTableName(s *ast.Schema, f *ast.FieldDefinition, args map[string]any) tableDefinition {
+	tableDef := getTableNameFromField(s, f)
+	tableDef.function = schema.GetSQLMutationDirective(f)
+	tableDef.args = functionArgumentValues(s, f, args)
+	return tableDef
+}
+
+// functionArgumentValues returns the values of the arguments passed to the function of the field, in order. List
+// values are passed as arrays cast to the type of their elements, so the function is resolved by its parameter types
+func functionArgumentValues(s *ast.Schema, f *ast.FieldDefinition, args map[string]any) []any {
+	values := make([]any, 0, len(f.Arguments))
+	for _, a := range schema.FunctionArguments(f) {
+		value := args[a.Name]
+		if value == nil || a.Type.Elem == nil {
+			values = append(values, value)
+			continue
+		}
+		array := arrayValue(value)
+		if sqlType := functionArrayType(s, a.Type.Elem.Name()); sqlType != "" {
+			values = append(values, goqu.L(fmt.Sprintf("?::%s[]", sqlType), array))
+			continue
+		}
+		values = append(values, array)
+	}
+	return values
+}
+
+// functionArrayType returns the SQL type of the elements of a list argument, i.e. integer for [Int!], custom scalars
+// use their @scalar cast type
+func functionArrayType(s *ast.Schema, typeName string) string {
+	switch typeName {
+	case "Int":
+		return "integer"
+	case "Float":
+		return "numeric"
+	case "Boolean":
+		return "boolean"
+	case "String", "ID":
+		return "text"
+	}
+	if sc, ok := schema.GetScalar(s, typeName); ok {
+		return sc.Cast
+	}
+	return ""
 }
 
 // getCreateTableName returns the field's type tableDefinition name in the database, if no directive is defined, type name is presumed
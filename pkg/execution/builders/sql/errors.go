@@ -0,0 +1,83 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+// queryCanceledCode is the SQLSTATE of statements canceled by the server, i.e. by statement_timeout
+const queryCanceledCode = "57014"
+
+// keyDetailRegex matches the columns of the detail of unique and foreign key violations, i.e. Key (email)=(a@b.c)
+var keyDetailRegex = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// queryError returns the error of a query executed with the statement timeout as a typed builders.Error, Postgres
+// errors are classified by their SQLSTATE. Other errors are returned as is.
+func queryError(ctx context.Context, timeout time.Duration, err error) error {
+	if err == nil {
+		return nil
+	}
+	if isStatementTimeout(ctx, timeout, err) {
+		return &builders.Error{
+			Code:    builders.ErrorCodeTimeout,
+			Message: fmt.Sprintf("query exceeded the statement timeout of %s", timeout),
+			Err:     err,
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) || pgxscan.NotFound(err) {
+		return &builders.Error{Code: builders.ErrorCodeNotFound, Message: "no rows found", Err: err}
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	e := &builders.Error{
+		Code:       sqlStateErrorCode(pgErr.Code),
+		Message:    pgErr.Message,
+		Column:     pgErr.ColumnName,
+		Constraint: pgErr.ConstraintName,
+		Err:        err,
+	}
+	if m := keyDetailRegex.FindStringSubmatch(pgErr.Detail); e.Column == "" && m != nil {
+		e.Column = m[1]
+	}
+	return e
+}
+
+// sqlStateErrorCode maps a Postgres SQLSTATE onto the code of the error, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+func sqlStateErrorCode(state string) builders.ErrorCode {
+	switch {
+	case strings.HasPrefix(state, "23"): // integrity constraint violation
+		return builders.ErrorCodeConstraintViolation
+	case state == "42501", strings.HasPrefix(state, "28"): // insufficient privilege, invalid authorization
+		return builders.ErrorCodePermission
+	case state == "02000", state == "P0002": // no data, no data found raised by a function
+		return builders.ErrorCodeNotFound
+	case strings.HasPrefix(state, "22"), state == "P0001": // data exception, exception raised by a function
+		return builders.ErrorCodeValidation
+	case state == queryCanceledCode:
+		return builders.ErrorCodeTimeout
+	}
+	return builders.ErrorCodeInternal
+}
+
+// isStatementTimeout returns true if err was caused by the statement timeout of a query executed in ctx, queries
+// canceled by ctx itself, i.e. a closed request, aren't statement timeouts
+func isStatementTimeout(ctx context.Context, timeout time.Duration, err error) bool {
+	if timeout <= 0 || ctx.Err() != nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	return pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &pgErr) && pgErr.Code == queryCanceledCode)
+}
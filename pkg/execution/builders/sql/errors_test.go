@@ -0,0 +1,114 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+func Test_queryError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	otherErr := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		timeout  time.Duration
+		err      error
+		expected *builders.Error
+	}{
+		{
+			name:     "deadline_exceeded",
+			ctx:      context.Background(),
+			timeout:  time.Second,
+			err:      fmt.Errorf("query failed: %w", context.DeadlineExceeded),
+			expected: &builders.Error{Code: builders.ErrorCodeTimeout, Message: "query exceeded the statement timeout of 1s"},
+		},
+		{
+			name:     "statement_canceled_by_server",
+			ctx:      context.Background(),
+			timeout:  time.Second,
+			err:      &pgconn.PgError{Code: queryCanceledCode, Message: "canceling statement due to statement timeout"},
+			expected: &builders.Error{Code: builders.ErrorCodeTimeout, Message: "query exceeded the statement timeout of 1s"},
+		},
+		{
+			name:    "request_canceled",
+			ctx:     canceled,
+			timeout: time.Second,
+			err:     context.Canceled,
+		},
+		{
+			name: "deadline_without_timeout",
+			ctx:  context.Background(),
+			err:  context.DeadlineExceeded,
+		},
+		{
+			name: "other_error",
+			ctx:  context.Background(),
+			err:  otherErr,
+		},
+		{
+			name:     "no_rows",
+			ctx:      context.Background(),
+			err:      pgx.ErrNoRows,
+			expected: &builders.Error{Code: builders.ErrorCodeNotFound, Message: "no rows found"},
+		},
+		{
+			name: "unique_violation",
+			ctx:  context.Background(),
+			err: &pgconn.PgError{Code: "23505", Message: `duplicate key value violates unique constraint "users_email_key"`,
+				Detail: "Key (email)=(a@b.c) already exists.", ConstraintName: "users_email_key"},
+			expected: &builders.Error{Code: builders.ErrorCodeConstraintViolation, Message: `duplicate key value violates unique constraint "users_email_key"`,
+				Column: "email", Constraint: "users_email_key"},
+		},
+		{
+			name: "not_null_violation",
+			ctx:  context.Background(),
+			err:  &pgconn.PgError{Code: "23502", Message: `null value in column "name" violates not-null constraint`, ColumnName: "name"},
+			expected: &builders.Error{Code: builders.ErrorCodeConstraintViolation, Message: `null value in column "name" violates not-null constraint`,
+				Column: "name"},
+		},
+		{
+			name:     "insufficient_privilege",
+			ctx:      context.Background(),
+			err:      &pgconn.PgError{Code: "42501", Message: "permission denied for table users"},
+			expected: &builders.Error{Code: builders.ErrorCodePermission, Message: "permission denied for table users"},
+		},
+		{
+			name:     "invalid_text_representation",
+			ctx:      context.Background(),
+			err:      &pgconn.PgError{Code: "22P02", Message: `invalid input syntax for type integer: "a"`},
+			expected: &builders.Error{Code: builders.ErrorCodeValidation, Message: `invalid input syntax for type integer: "a"`},
+		},
+		{
+			name:     "undefined_column",
+			ctx:      context.Background(),
+			err:      &pgconn.PgError{Code: "42703", Message: `column "nme" does not exist`},
+			expected: &builders.Error{Code: builders.ErrorCodeInternal, Message: `column "nme" does not exist`},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := queryError(tc.ctx, tc.timeout, tc.err)
+			if tc.expected == nil {
+				assert.Equal(t, tc.err, err)
+				return
+			}
+			var e *builders.Error
+			require.ErrorAs(t, err, &e)
+			tc.expected.Err = tc.err
+			assert.Equal(t, tc.expected, e)
+		})
+	}
+	assert.NoError(t, queryError(context.Background(), time.Second, nil))
+}
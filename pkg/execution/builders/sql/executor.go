@@ -2,20 +2,34 @@ package sql
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/roneli/fastgql/pkg/execution"
 	"github.com/roneli/fastgql/pkg/execution/builders"
 )
 
 // Executor implements execution.Executor for PostgreSQL databases.
 type Executor struct {
-	pool    *pgxpool.Pool
-	config  *builders.Config
-	builder Builder
-	dialect string
+	pool      *pgxpool.Pool
+	config    *builders.Config
+	builder   Builder
+	dialect   string
+	telemetry *telemetry
+	// replicas are the read replica pools queries are sent to, see WithReplicas
+	replicas      []*pgxpool.Pool
+	replicaPolicy ReplicaPolicy
+	nextReplica   atomic.Uint64
+	// batchWarning warns once that BatchQueries is enabled without execution.BatchExtension
+	batchWarning sync.Once
 }
 
 // NewExecutor creates a new SQL Executor with the given pool and config.
@@ -25,76 +39,215 @@ func NewExecutor(pool *pgxpool.Pool, config *builders.Config) *Executor {
 		dialect = "postgres"
 	}
 	return &Executor{
-		pool:    pool,
-		config:  config,
-		builder: NewBuilder(config),
-		dialect: dialect,
+		pool:      pool,
+		config:    config,
+		builder:   NewBuilder(config),
+		dialect:   dialect,
+		telemetry: newTelemetry(config, dialect),
 	}
 }
 
 // Query executes a read query and scans results into dest.
 func (e *Executor) Query(ctx context.Context, dest any) error {
-	query, args, err := buildReadQuery(ctx, e.builder)
+	query, args, err := e.telemetry.build(ctx, string(builders.QueryOperation), func() (string, []any, error) {
+		return buildReadQuery(ctx, e.builder)
+	})
 	if err != nil {
-		return err
+		return builders.GQLError(err)
 	}
 
-	rows, err := e.pool.Query(ctx, query, args...)
+	scan := func(rows pgx.Rows) (int, error) {
+		return scanRows(dest, rows)
+	}
+	s, err := execution.GetStream(ctx)
 	if err != nil {
-		return err
+		return builders.GQLError(err)
 	}
-
-	// Determine if we're scanning a single row or multiple rows
-	destType := reflect.TypeOf(dest)
-	if destType.Kind() == reflect.Ptr {
-		destType = destType.Elem()
+	if s != nil {
+		return builders.GQLError(e.stream(ctx, s, dest, query, args, scan))
 	}
+	return builders.GQLError(e.telemetry.execute(ctx, string(builders.QueryOperation), query, func(ctx context.Context) (int, error) {
+		return e.batchQuery(ctx, &batchedQuery{sql: query, args: args, dest: dest, timeout: e.fieldStatementTimeout(ctx), scan: scan})
+	}))
+}
 
-	if destType.Kind() != reflect.Slice {
-		return pgxscan.ScanOne(dest, rows)
+// QueryField executes a read query for an already collected field and scans results into dest.
+// It is used by execution.MultiExecutor to batch resolve relations across dialects.
+func (e *Executor) QueryField(ctx context.Context, field builders.Field, dest any) error {
+	query, args, err := e.telemetry.build(ctx, string(builders.QueryOperation), func() (string, []any, error) {
+		return e.builder.Query(field)
+	})
+	if err != nil {
+		return builders.GQLError(err)
 	}
-	return pgxscan.ScanAll(dest, rows)
+
+	timeout := e.statementTimeout(ctx, field.TypeDefinition)
+	return builders.GQLError(e.execute(ctx, string(builders.QueryOperation), query, e.readPool(ctx), timeout, func(ctx context.Context, db database) (int, error) {
+		return scan(ctx, db, dest, query, args...)
+	}))
 }
 
 // QueryWithTypes handles interface types that need type discrimination.
 func (e *Executor) QueryWithTypes(ctx context.Context, dest any, types map[string]reflect.Type, typeKey string) error {
-	query, args, err := buildReadQuery(ctx, e.builder)
+	query, args, err := e.telemetry.build(ctx, string(builders.QueryOperation), func() (string, []any, error) {
+		return buildReadQuery(ctx, e.builder)
+	})
 	if err != nil {
-		return err
+		return builders.GQLError(err)
 	}
 
 	scanner := NewTypeNameScanner[any](types, typeKey)
-	results, err := collect(ctx, e.pool, func(row pgx.CollectableRow) (any, error) {
-		return scanner.ScanRow(row)
-	}, query, args...)
+	scan := func(rows pgx.Rows) (int, error) {
+		results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (any, error) {
+			return scanner.ScanRow(row)
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		// Set the results into dest using reflection
+		destVal := reflect.ValueOf(dest).Elem()
+		sliceVal := reflect.MakeSlice(destVal.Type(), len(results), len(results))
+		for i, r := range results {
+			sliceVal.Index(i).Set(reflect.ValueOf(r))
+		}
+		destVal.Set(sliceVal)
+		return len(results), nil
+	}
+	s, err := execution.GetStream(ctx)
+	if err != nil {
+		return builders.GQLError(err)
+	}
+	if s != nil {
+		return builders.GQLError(e.stream(ctx, s, dest, query, args, scan))
+	}
+	return builders.GQLError(e.telemetry.execute(ctx, string(builders.QueryOperation), query, func(ctx context.Context) (int, error) {
+		return e.batchQuery(ctx, &batchedQuery{sql: query, args: args, dest: dest, timeout: e.fieldStatementTimeout(ctx), scan: scan})
+	}))
+}
+
+// Mutate executes a create/update/delete or @sqlMutation mutation and scans results into dest.
+func (e *Executor) Mutate(ctx context.Context, dest any) error {
+	operation := string(builders.GetOperationType(ctx))
+	query, args, err := e.telemetry.build(ctx, operation, func() (string, []any, error) {
+		return buildMutationQuery(ctx, e.builder)
+	})
+	if err != nil {
+		return builders.GQLError(err)
+	}
+
+	return builders.GQLError(e.execute(ctx, operation, query, e.pool, e.fieldStatementTimeout(ctx), func(ctx context.Context, db database) (int, error) {
+		// @sqlMutation mutations return the rows of their function as the field's type, or a list of it
+		if operation == string(builders.FunctionOperation) {
+			return scan(ctx, db, dest, query, args...)
+		}
+		rows, err := db.Query(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+
+		// Mutations typically return a single row
+		return 1, pgxscan.ScanOne(dest, rows)
+	}))
+}
+
+// MutateWithTypes executes a create/update/delete mutation whose payload returns interface types, the returned objects
+// are scanned into their concrete type based on the typeKey discriminator.
+func (e *Executor) MutateWithTypes(ctx context.Context, dest any, types map[string]reflect.Type, typeKey string) error {
+	operation := string(builders.GetOperationType(ctx))
+	query, args, err := e.telemetry.build(ctx, operation, func() (string, []any, error) {
+		return buildMutationQuery(ctx, e.builder)
+	})
 	if err != nil {
-		return err
+		return builders.GQLError(err)
 	}
 
-	// Set the results into dest using reflection
+	return builders.GQLError(e.execute(ctx, operation, query, e.pool, e.fieldStatementTimeout(ctx), func(ctx context.Context, db database) (int, error) {
+		rows, err := db.Query(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+		m, err := pgx.CollectOneRow(rows, pgx.RowToMap)
+		if err != nil {
+			return 0, err
+		}
+		return 1, decodePayload(m, dest, NewTypeNameScanner[any](types, typeKey))
+	}))
+}
+
+// execute runs execFn on db bounded by the statement timeout, returning the errors of its queries as typed
+// builders.Error
+func (e *Executor) execute(ctx context.Context, operation, query string, db database, timeout time.Duration, execFn func(ctx context.Context, db database) (int, error)) error {
+	return e.telemetry.execute(ctx, operation, query, func(ctx context.Context) (int, error) {
+		n, err := withStatementTimeout(ctx, db, timeout, execFn)
+		return n, queryError(ctx, timeout, err)
+	})
+}
+
+// decodePayload decodes a mutation payload row into dest, a pointer to the payload struct. Columns are matched
+// by the json tag of the struct fields, slices of interfaces are decoded element by element using the scanner.
+func decodePayload(m map[string]any, dest any, scanner *TypeNameScanner[any]) error {
 	destVal := reflect.ValueOf(dest).Elem()
-	sliceVal := reflect.MakeSlice(destVal.Type(), len(results), len(results))
-	for i, r := range results {
-		sliceVal.Index(i).Set(reflect.ValueOf(r))
+	destType := destVal.Type()
+	for i := 0; i < destType.NumField(); i++ {
+		sf := destType.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		value, ok := m[name]
+		if !ok || value == nil {
+			continue
+		}
+		fv := destVal.Field(i)
+		if sf.Type.Kind() != reflect.Slice || sf.Type.Elem().Kind() != reflect.Interface {
+			if err := mapstructure.Decode(value, fv.Addr().Interface()); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", name, err)
+			}
+			continue
+		}
+		elems, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected list for %s got %T", name, value)
+		}
+		sliceVal := reflect.MakeSlice(sf.Type, len(elems), len(elems))
+		for j, elem := range elems {
+			em, ok := elem.(map[string]any)
+			if !ok {
+				return fmt.Errorf("expected object in %s got %T", name, elem)
+			}
+			v, err := scanner.ScanMap(em)
+			if err != nil {
+				return err
+			}
+			sliceVal.Index(j).Set(reflect.ValueOf(v))
+		}
+		fv.Set(sliceVal)
 	}
-	destVal.Set(sliceVal)
 	return nil
 }
 
-// Mutate executes a create/update/delete mutation and scans results into dest.
-func (e *Executor) Mutate(ctx context.Context, dest any) error {
-	query, args, err := buildMutationQuery(ctx, e.builder)
+// scan executes the query on db and scans the rows into dest, returning the number of rows scanned
+func scan(ctx context.Context, db database, dest any, query string, args ...any) (int, error) {
+	rows, err := db.Query(ctx, query, args...)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return scanRows(dest, rows)
+}
 
-	rows, err := e.pool.Query(ctx, query, args...)
-	if err != nil {
-		return err
+// scanRows scans the rows into dest, a pointer to a slice or to a single object
+func scanRows(dest any, rows pgx.Rows) (int, error) {
+	// Determine if we're scanning a single row or multiple rows
+	destType := reflect.TypeOf(dest)
+	if destType.Kind() == reflect.Ptr {
+		destType = destType.Elem()
 	}
 
-	// Mutations typically return a single row
-	return pgxscan.ScanOne(dest, rows)
+	if destType.Kind() != reflect.Slice {
+		return 1, pgxscan.ScanOne(dest, rows)
+	}
+	if err := pgxscan.ScanAll(dest, rows); err != nil {
+		return 0, err
+	}
+	return reflect.ValueOf(dest).Elem().Len(), nil
 }
 
 // Dialect returns the SQL dialect name.
@@ -1,11 +1,21 @@
 package sql
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/roneli/fastgql/pkg/execution/builders"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestNewExecutor(t *testing.T) {
@@ -69,3 +79,117 @@ func TestExecutor_Dialect(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutor_Telemetry(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	executor := NewExecutor(nil, &builders.Config{
+		Schema:         &ast.Schema{},
+		TracerProvider: tp,
+		MeterProvider:  mp,
+	})
+	ctx := context.Background()
+
+	query, _, err := executor.telemetry.build(ctx, "query", func() (string, []any, error) {
+		return `SELECT "sq0"."name" AS "name" FROM "users" AS "sq0" WHERE "sq0"."name" = 'alice' LIMIT $1`, []any{100}, nil
+	})
+	require.NoError(t, err)
+	err = executor.telemetry.execute(ctx, "query", query, func(ctx context.Context) (int, error) {
+		return 3, nil
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "fastgql.build", spans[0].Name)
+	assert.Equal(t, "fastgql.execute", spans[1].Name)
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[1].Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "postgresql", attrs["db.system"].AsString())
+	assert.Equal(t, `SELECT "sq0"."name" AS "name" FROM "users" AS "sq0" WHERE "sq0"."name" = ? LIMIT $1`, attrs["db.statement"].AsString())
+	assert.Equal(t, int64(3), attrs["db.response.returned_rows"].AsInt64())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	names := make([]string, 0)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		names = append(names, m.Name)
+	}
+	assert.ElementsMatch(t, []string{"fastgql.build.duration", "fastgql.db.duration", "fastgql.result.size"}, names)
+
+	t.Run("build_error_recorded", func(t *testing.T) {
+		exporter.Reset()
+		_, _, err := executor.telemetry.build(ctx, "query", func() (string, []any, error) {
+			return "", nil, errors.New("failed")
+		})
+		require.Error(t, err)
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+	})
+}
+
+func Test_sanitizeStatement(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "prepared_placeholders_kept",
+			query:    `SELECT "sq0"."id" FROM "users" AS "sq0" LIMIT $1`,
+			expected: `SELECT "sq0"."id" FROM "users" AS "sq0" LIMIT $1`,
+		},
+		{
+			name:     "string_and_number_literals",
+			query:    `INSERT INTO "posts" AS "sq0" ("id", "name") VALUES (111, 'Ron''s')`,
+			expected: `INSERT INTO "posts" AS "sq0" ("id", "name") VALUES (?, ?)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeStatement(tt.query))
+		})
+	}
+}
+
+func Test_decodePayload(t *testing.T) {
+	type animalsPayload struct {
+		RowsAffected int   `json:"rows_affected"`
+		Animals      []any `json:"animals"`
+	}
+	scanner := NewTypeNameScanner[any](map[string]reflect.Type{
+		"Cat": reflect.TypeOf(Cat{}),
+		"Dog": reflect.TypeOf(Dog{}),
+	}, "type")
+
+	t.Run("interface_list", func(t *testing.T) {
+		var data animalsPayload
+		err := decodePayload(map[string]any{
+			"rows_affected": int64(2),
+			"animals": []any{
+				map[string]any{"id": float64(1), "name": "Tom", "type": "cat", "color": "grey"},
+				map[string]any{"id": float64(2), "name": "Rex", "type": "dog", "breed": "lab"},
+			},
+		}, &data, scanner)
+		require.NoError(t, err)
+		assert.Equal(t, 2, data.RowsAffected)
+		require.Len(t, data.Animals, 2)
+		assert.Equal(t, &Cat{ID: 1, Name: "Tom", Type: "cat", Color: "grey"}, data.Animals[0])
+		assert.Equal(t, &Dog{ID: 2, Name: "Rex", Type: "dog", Breed: "lab"}, data.Animals[1])
+	})
+
+	t.Run("unknown_type", func(t *testing.T) {
+		var data animalsPayload
+		err := decodePayload(map[string]any{
+			"animals": []any{map[string]any{"id": float64(1), "type": "bird"}},
+		}, &data, scanner)
+		require.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
@@ -23,6 +24,25 @@ var defaultOperators = map[string]builders.Operator{
 	"lt":     opLt,
 	"prefix": opPrefix,
 	"suffix": opSuffix,
+	// range operator of the DateComparator, DateTimeComparator and TimeOfDayComparator
+	"between": opBetween,
+	// PostGIS operators of the GeometryComparator and GeographyComparator
+	"intersects":    opIntersects,
+	"within":        opWithin,
+	"dWithin":       opDWithin,
+	"containsPoint": opContainsPoint,
+}
+
+// defaultListOperators are operators of list comparators that apply only to array columns, they take precedence over
+// the operators of the same name in defaultOperators.
+var defaultListOperators = map[string]builders.Operator{
+	"eq":          opArrayEq,
+	"neq":         opArrayNeq,
+	"contains":    opArrayContains,
+	"containedBy": opArrayContainedBy,
+	// contained is the containedBy operator of the built-in Int, Float and Boolean list comparators
+	"contained": opArrayContainedBy,
+	"overlap":   opArrayOverlap,
 }
 
 func opEq(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
@@ -80,3 +100,51 @@ func opPrefix(table exp.AliasedExpression, key string, value interface{}) goqu.E
 func opSuffix(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
 	return table.Col(key).Like(fmt.Sprintf("%%%s", value))
 }
+
+func opArrayEq(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.L("? = ?", table.Col(key), arrayLiteral(value))
+}
+
+func opArrayNeq(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.L("? <> ?", table.Col(key), arrayLiteral(value))
+}
+
+func opArrayContains(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.L("? @> ?", table.Col(key), arrayLiteral(value))
+}
+
+func opArrayContainedBy(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.L("? <@ ?", table.Col(key), arrayLiteral(value))
+}
+
+func opArrayOverlap(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.L("? && ?", table.Col(key), arrayLiteral(value))
+}
+
+// arrayElementEscaper escapes the quoted elements of an array literal
+var arrayElementEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// arrayValue builds an array constructor of the list value's elements, i.e. ARRAY[$1, $2]
+func arrayValue(value interface{}) exp.LiteralExpression {
+	values := cast.ToSlice(value)
+	if len(values) == 0 {
+		return goqu.L("'{}'")
+	}
+	return goqu.L(fmt.Sprintf("ARRAY[%s]", strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")), values...)
+}
+
+// arrayLiteral builds the array literal of the list value's elements, i.e. {"BUG","FEATURE"}. The literal is bound as a
+// single untyped parameter, so Postgres casts it to the type of the compared array column, i.e. an enum array, where
+// ARRAY[$1, $2] would be a text array.
+func arrayLiteral(value interface{}) string {
+	values := cast.ToSlice(value)
+	elements := make([]string, len(values))
+	for i, v := range values {
+		if v == nil {
+			elements[i] = "NULL"
+			continue
+		}
+		elements[i] = `"` + arrayElementEscaper.Replace(cast.ToString(v)) + `"`
+	}
+	return "{" + strings.Join(elements, ",") + "}"
+}
@@ -59,6 +59,13 @@ func TestOperators(t *testing.T) {
 
 		// Suffix operator
 		{"suffix_string", "suffix", "email", "@gmail.com", `"u"."email" LIKE '%@gmail.com'`},
+
+		// PostGIS operators
+		{"intersects_object", "intersects", "area", map[string]any{"type": "Point", "coordinates": []any{1, 2}}, `ST_Intersects("u"."area", ST_GeomFromGeoJSON('{"coordinates":[1,2],"type":"Point"}'))`},
+		{"within_string", "within", "location", `{"type":"Point","coordinates":[1,2]}`, `ST_Within("u"."location", ST_GeomFromGeoJSON('{"type":"Point","coordinates":[1,2]}'))`},
+		{"containsPoint", "containsPoint", "area", map[string]any{"type": "Point", "coordinates": []any{1, 2}}, `ST_Contains("u"."area", ST_GeomFromGeoJSON('{"coordinates":[1,2],"type":"Point"}'))`},
+		{"between", "between", "created_at", map[string]any{"from": "2024-01-01", "to": "2024-02-01"}, `("u"."created_at" BETWEEN '2024-01-01' AND '2024-02-01')`},
+		{"dWithin", "dWithin", "location", map[string]any{"point": map[string]any{"type": "Point", "coordinates": []any{1, 2}}, "distance": 100}, `ST_DWithin("u"."location", ST_GeomFromGeoJSON('{"coordinates":[1,2],"type":"Point"}'), 100)`},
 	}
 
 	for _, tt := range tests {
@@ -74,6 +81,35 @@ func TestOperators(t *testing.T) {
 	}
 }
 
+func TestTemporalOperators(t *testing.T) {
+	table := goqu.T("events").As("e")
+
+	tests := []struct {
+		name        string
+		scalar      string
+		operator    string
+		value       interface{}
+		wantContain string
+	}{
+		{"olderThan_date", "Date", "olderThan", "1 year", `("e"."created_at" < now() - '1 year'::interval)`},
+		{"within_datetime", "DateTime", "within", "7 days", `("e"."created_at" BETWEEN now() - '7 days'::interval AND now())`},
+		{"dayOfWeek", "DateTime", "dayOfWeek", 7, `(EXTRACT(ISODOW FROM "e"."created_at") = 7)`},
+		{"year", "Date", "year", 2024, `(EXTRACT(YEAR FROM "e"."created_at") = 2024)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, ok := defaultScalarOperators[tt.scalar][tt.operator]
+			assert.True(t, ok, "operator %s should exist for %s", tt.operator, tt.scalar)
+
+			expr := op(table, "created_at", tt.value)
+			sql, _, err := goqu.Dialect("postgres").Select().Where(expr).ToSQL()
+			assert.NoError(t, err)
+			assert.Contains(t, sql, tt.wantContain)
+		})
+	}
+}
+
 func TestIsNullOperator(t *testing.T) {
 	table := goqu.T("users").As("u")
 
@@ -104,7 +140,8 @@ func TestIsNullOperator(t *testing.T) {
 func TestAllDefaultOperatorsExist(t *testing.T) {
 	expectedOperators := []string{
 		"eq", "neq", "like", "ilike", "notIn", "in",
-		"isNull", "gt", "gte", "lte", "lt", "prefix", "suffix",
+		"isNull", "gt", "gte", "lte", "lt", "prefix", "suffix", "between",
+		"intersects", "within", "dWithin", "containsPoint",
 	}
 
 	for _, opName := range expectedOperators {
@@ -150,3 +187,29 @@ func TestOperatorEdgeCases(t *testing.T) {
 		assert.Contains(t, sql, `"t"."id" IN (1)`)
 	})
 }
+
+func TestListOperators(t *testing.T) {
+	table := goqu.T("tickets").As("t")
+
+	tests := []struct {
+		name        string
+		operator    string
+		value       interface{}
+		wantContain string
+	}{
+		{"contains_enum", "contains", []any{"BUG", "FEATURE"}, `"t"."labels" @> '{"BUG","FEATURE"}'`},
+		{"overlap_int", "overlap", []any{int64(1), int64(2)}, `"t"."labels" && '{"1","2"}'`},
+		{"eq_null_element", "eq", []any{"BUG", nil}, `"t"."labels" = '{"BUG",NULL}'`},
+		{"containedBy_escaped", "containedBy", []any{`a"b\c`}, `"t"."labels" <@ '{"a\"b\\c"}'`},
+		{"neq_empty", "neq", []any{}, `"t"."labels" <> '{}'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := defaultListOperators[tt.operator](table, "labels", tt.value)
+			sql, _, err := goqu.Dialect("postgres").Select().Where(expr).ToSQL()
+			assert.NoError(t, err)
+			assert.Contains(t, sql, tt.wantContain)
+		})
+	}
+}
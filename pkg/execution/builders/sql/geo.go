@@ -0,0 +1,65 @@
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/spf13/cast"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/schema"
+)
+
+// geoJSONValue returns the GeoJSON text of a Geometry or Geography argument, given either as an object or a string
+func geoJSONValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// geoFromGeoJSON converts a GeoJSON argument to a PostGIS geometry, geometries are implicitly cast to geography
+func geoFromGeoJSON(value any) exp.LiteralExpression {
+	return goqu.L("ST_GeomFromGeoJSON(?)", geoJSONValue(value))
+}
+
+// geoSelect selects a Geometry or Geography column as a GeoJSON object
+func geoSelect(col exp.IdentifierExpression) exp.LiteralExpression {
+	return goqu.L("ST_AsGeoJSON(?)::jsonb", col)
+}
+
+// isGeoField returns true if the field definition's type is the Geometry or Geography scalar
+func isGeoField(def *ast.Definition, fieldName string) bool {
+	if def == nil {
+		return false
+	}
+	f := def.Fields.ForName(fieldName)
+	return f != nil && schema.IsGeoType(f.Type.Name())
+}
+
+func opIntersects(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.Func("ST_Intersects", table.Col(key), geoFromGeoJSON(value))
+}
+
+func opWithin(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.Func("ST_Within", table.Col(key), geoFromGeoJSON(value))
+}
+
+func opContainsPoint(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.Func("ST_Contains", table.Col(key), geoFromGeoJSON(value))
+}
+
+// opDWithin matches geometries within distance of a point, value is a DWithinInput {point, distance}
+func opDWithin(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	m := cast.ToStringMap(value)
+	return goqu.Func("ST_DWithin", table.Col(key), geoFromGeoJSON(m["point"]), cast.ToFloat64(m["distance"]))
+}
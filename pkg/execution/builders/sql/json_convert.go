@@ -191,6 +191,13 @@ func buildOperators(path string, opMap map[string]any) ([]JSONPathExpr, error) {
 			}
 			exprs = append(exprs, expr)
 
+		case "in", "notIn":
+			expr, err := buildIn(path, value, op == "notIn")
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+
 		default:
 			expr, err := JsonExpr(path, op, value)
 			if err != nil {
@@ -202,6 +209,26 @@ func buildOperators(path string, opMap map[string]any) ([]JSONPathExpr, error) {
 	return exprs, nil
 }
 
+// buildIn builds an OR of equality conditions of the values, negated for notIn
+func buildIn(path string, value any, negate bool) (JSONPathExpr, error) {
+	values, ok := value.([]any)
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("in value must be a non-empty list")
+	}
+	exprs := make([]JSONPathExpr, 0, len(values))
+	for _, v := range values {
+		expr, err := JsonExpr(path, "eq", v)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	if negate {
+		return JsonNot(JsonOr(exprs...)), nil
+	}
+	return JsonOr(exprs...), nil
+}
+
 // buildNestedField recursively builds expressions for nested JSON fields
 func buildNestedField(basePath string, filterMap map[string]any) ([]JSONPathExpr, error) {
 	var exprs []JSONPathExpr
@@ -3,7 +3,9 @@ package sql
 import (
 	"testing"
 
+	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -234,3 +236,25 @@ func TestConvertFilterMapAllOperator(t *testing.T) {
 func ptrBool(b bool) *bool {
 	return &b
 }
+
+func TestConvertFilterMapWithInOperators(t *testing.T) {
+	dialect := GetSQLDialect("postgres")
+	col := exp.NewIdentifierExpression("", "test", "data")
+
+	filterMap := map[string]any{
+		"status": map[string]any{
+			"in":    []any{"ACTIVE", "PENDING"},
+			"notIn": []any{"DELETED"},
+		},
+	}
+
+	expr, err := ConvertFilterMapToExpression(col, filterMap, dialect)
+	require.NoError(t, err)
+	query, args, err := goqu.Dialect("postgres").From("test").Where(expr).Prepared(true).ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "test" WHERE jsonb_path_exists("test"."data", $1::jsonpath, $2::jsonb)`, query)
+	assert.Equal(t, []any{"$ ? ((@.status == $v0 || @.status == $v1) && !(@.status == $v2))", `{"v0":"ACTIVE","v1":"PENDING","v2":"DELETED"}`}, args)
+
+	_, err = ConvertFilterMapToExpression(col, map[string]any{"status": map[string]any{"in": []any{}}}, dialect)
+	require.Error(t, err)
+}
@@ -63,6 +63,8 @@ var knownOperators = map[string]bool{
 	"prefix":   true,
 	"suffix":   true,
 	"contains": true,
+	"in":       true,
+	"notIn":    true,
 	"isNull":   true,
 	"any":      true,
 	"all":      true,
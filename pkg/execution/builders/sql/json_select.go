@@ -6,6 +6,8 @@ import (
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/roneli/fastgql/pkg/execution/builders"
+	"github.com/roneli/fastgql/pkg/schema"
+	"github.com/spf13/cast"
 )
 
 // BuildJsonFieldObject builds an expression to extract selected JSON fields
@@ -15,6 +17,21 @@ func BuildJsonFieldObject(
 	selections builders.Fields,
 	dialect string,
 ) (exp.Expression, error) {
+	return buildJsonObject(baseCol, selections, dialect, 0)
+}
+
+// BuildJsonFieldArray builds an expression projecting each element of a JSON array of objects, elements are expanded
+// using jsonb_array_elements, filtered, ordered and paginated by the field's filter, orderBy, limit and offset arguments,
+// and aggregated back into an array of the selected fields
+func BuildJsonFieldArray(
+	baseCol exp.Expression,
+	field builders.Field,
+	dialect string,
+) (exp.Expression, error) {
+	return buildJsonArray(baseCol, field, dialect, 0)
+}
+
+func buildJsonObject(baseCol exp.Expression, selections builders.Fields, dialect string, depth int) (exp.Expression, error) {
 	if len(selections) == 0 {
 		// No selections - this is invalid for field selection
 		// If you want the entire JSON object, you should select it as a Map scalar, not as a typed JSON field
@@ -46,12 +63,17 @@ func BuildJsonFieldObject(
 			}
 			// Extract the nested object using -> operator (more efficient than jsonb_path_query_first for simple paths)
 			nestedCol := goqu.L("?->?", baseCol, sel.Name)
-			// Recursively build the nested object structure
-			nestedObj, err := BuildJsonFieldObject(nestedCol, sel.Selections, dialect)
+			var err error
+			if isJsonArray(sel) {
+				// Nested array of objects: project each element of the array
+				valueExpr, err = buildJsonArray(nestedCol, sel, dialect, depth+1)
+			} else {
+				// Recursively build the nested object structure
+				valueExpr, err = buildJsonObject(nestedCol, sel.Selections, dialect, depth)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("building nested JSON for %s: %w", sel.Name, err)
 			}
-			valueExpr = nestedObj
 
 		default:
 			return nil, fmt.Errorf("unsupported field type %s in JSON selection", sel.FieldType)
@@ -63,3 +85,86 @@ func BuildJsonFieldObject(
 	sqlDialect := GetSQLDialect(dialect)
 	return sqlDialect.JSONBuildObject(args...), nil
 }
+
+// buildJsonArray builds a sub query aggregating the selected fields of each element of the JSON array, i.e.
+// (SELECT COALESCE(jsonb_agg(jsonb_build_object('sku', "elem0"->'sku') ORDER BY "elem0"->'sku' ASC NULLS LAST), '[]'::jsonb)
+// FROM (SELECT "elem0" FROM jsonb_array_elements(col) AS "elem0" ORDER BY "elem0"->'sku' ASC NULLS LAST LIMIT 10) AS "elems0").
+// The depth is used to alias the elements of nested arrays.
+func buildJsonArray(baseCol exp.Expression, field builders.Field, dialect string, depth int) (exp.Expression, error) {
+	sqlDialect := GetSQLDialect(dialect)
+	elemName := fmt.Sprintf("elem%d", depth)
+	elem := goqu.I(elemName)
+	elems := goqu.Dialect(dialect).From(goqu.Func("jsonb_array_elements", baseCol).As(elemName)).Select(elem).Prepared(true)
+
+	if filterArg, ok := field.Arguments["filter"]; ok && filterArg != nil {
+		filterMap, ok := filterArg.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected filter arg type %T", filterArg)
+		}
+		if len(filterMap) > 0 {
+			filterExp, err := ConvertFilterMapToExpression(elem, filterMap, sqlDialect)
+			if err != nil {
+				return nil, fmt.Errorf("building JSON array filter for %s: %w", field.Name, err)
+			}
+			elems = elems.Where(filterExp)
+		}
+	}
+	var orderBy []exp.OrderedExpression
+	if orderArg, ok := field.Arguments["orderBy"]; ok && orderArg != nil {
+		// elements of JSON arrays are ordered by their leaf fields only
+		orderFields, err := builders.CollectOrdering(orderArg, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range orderFields {
+			if err := validatePath(o.Key); err != nil {
+				return nil, fmt.Errorf("invalid JSON ordering field %s: %w", o.Key, err)
+			}
+			if orderExp := orderExpression(goqu.L("?->?", elem, o.Key), o.Type); orderExp != nil {
+				orderBy = append(orderBy, orderExp)
+			}
+		}
+		// the elements are ordered before they're paginated, and again in the aggregate since the order of the
+		// sub query's rows isn't kept by jsonb_agg
+		elems = elems.Order(orderBy...)
+	}
+	if limit, ok := field.Arguments["limit"]; ok && limit != nil {
+		elems = elems.Limit(cast.ToUint(limit))
+	}
+	if offset, ok := field.Arguments["offset"]; ok && offset != nil {
+		elems = elems.Offset(cast.ToUint(offset))
+	}
+
+	obj, err := buildJsonObject(elem, field.Selections, dialect, depth)
+	if err != nil {
+		return nil, err
+	}
+	agg := sqlDialect.CoalesceJSON(sqlDialect.JSONAggOrdered(obj, orderBy...), "'[]'::jsonb")
+	return goqu.L("?", goqu.Dialect(dialect).From(elems.As(fmt.Sprintf("elems%d", depth))).Select(agg).Prepared(true)), nil
+}
+
+// jsonPathValue extracts the value at the keys of the JSON column, cast to numeric or boolean for Int, Float and
+// Boolean fields so they are ordered and grouped by value rather than text, i.e. ("attributes"->'details'->>'price')::numeric.
+// The keys are GraphQL field names and are inlined, so the same expression can be selected and grouped by.
+func jsonPathValue(col exp.Expression, keys []string, typeName string) exp.LiteralExpression {
+	path := "?"
+	for i, k := range keys {
+		op := "->"
+		if i == len(keys)-1 {
+			op = "->>"
+		}
+		path += fmt.Sprintf("%s'%s'", op, k)
+	}
+	switch typeName {
+	case "Int", "Float":
+		return goqu.L(fmt.Sprintf("(%s)::numeric", path), col)
+	case "Boolean":
+		return goqu.L(fmt.Sprintf("(%s)::boolean", path), col)
+	}
+	return goqu.L(path, col)
+}
+
+// isJsonArray checks if the JSON field is a list of objects
+func isJsonArray(field builders.Field) bool {
+	return field.Field != nil && field.Definition != nil && schema.IsListType(field.Definition.Type)
+}
@@ -198,3 +198,97 @@ func TestBuildJsonFieldObject(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildJsonFieldArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    builders.Field
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "array of objects",
+			field: builders.Field{
+				Field: &ast.Field{Name: "reviews"},
+				Selections: builders.Fields{
+					{Field: &ast.Field{Name: "rating"}, FieldType: builders.TypeScalar},
+				},
+			},
+			wantSQL:  `SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('rating', "elem0"->$1)), '[]'::jsonb) FROM (SELECT "elem0" FROM jsonb_array_elements("test"."reviews") AS "elem0") AS "elems0") FROM "test"`,
+			wantArgs: []interface{}{"rating"},
+		},
+		{
+			name: "array with filter, ordering and pagination",
+			field: builders.Field{
+				Field: &ast.Field{Name: "reviews"},
+				Selections: builders.Fields{
+					{Field: &ast.Field{Name: "rating"}, FieldType: builders.TypeScalar},
+				},
+				Arguments: map[string]interface{}{
+					"filter":  map[string]any{"rating": map[string]any{"gt": 3}},
+					"orderBy": []interface{}{map[string]interface{}{"rating": "DESC"}},
+					"limit":   2,
+					"offset":  1,
+				},
+			},
+			wantSQL:  `SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('rating', "elem0"->$1) ORDER BY "elem0"->$2 DESC NULLS LAST), '[]'::jsonb) FROM (SELECT "elem0" FROM jsonb_array_elements("test"."reviews") AS "elem0" WHERE jsonb_path_exists("elem0", $3::jsonpath, $4::jsonb) ORDER BY "elem0"->$5 DESC NULLS LAST LIMIT $6 OFFSET $7) AS "elems0") FROM "test"`,
+			wantArgs: []interface{}{"rating", "rating", `$ ? (@.rating > $v0)`, `{"v0":3}`, "rating", int64(2), int64(1)},
+		},
+		{
+			name: "array nested in array elements",
+			field: builders.Field{
+				Field: &ast.Field{Name: "variants"},
+				Selections: builders.Fields{
+					{
+						Field:     &ast.Field{Name: "parts", Definition: &ast.FieldDefinition{Type: ast.ListType(ast.NamedType("Part", nil), nil)}},
+						FieldType: builders.TypeObject,
+						Selections: builders.Fields{
+							{Field: &ast.Field{Name: "sku"}, FieldType: builders.TypeScalar},
+						},
+					},
+				},
+			},
+			wantSQL:  `SELECT (SELECT COALESCE(jsonb_agg(jsonb_build_object('parts', (SELECT COALESCE(jsonb_agg(jsonb_build_object('sku', "elem1"->$1)), '[]'::jsonb) FROM (SELECT "elem1" FROM jsonb_array_elements("elem0"->$2) AS "elem1") AS "elems1"))), '[]'::jsonb) FROM (SELECT "elem0" FROM jsonb_array_elements("test"."reviews") AS "elem0") AS "elems0") FROM "test"`,
+			wantArgs: []interface{}{"sku", "parts"},
+		},
+		{
+			name: "invalid ordering field returns error",
+			field: builders.Field{
+				Field: &ast.Field{Name: "reviews"},
+				Selections: builders.Fields{
+					{Field: &ast.Field{Name: "rating"}, FieldType: builders.TypeScalar},
+				},
+				Arguments: map[string]interface{}{
+					"orderBy": []interface{}{map[string]interface{}{"rating'": "DESC"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "empty selections returns error",
+			field:   builders.Field{Field: &ast.Field{Name: "reviews"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := goqu.T("test").Col("reviews")
+			expr, err := BuildJsonFieldArray(col, tt.field, "postgres")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			query := goqu.Dialect("postgres").From("test").Select(expr).Prepared(true)
+			sqlStr, args, err := query.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSQL, sqlStr)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
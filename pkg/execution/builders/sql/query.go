@@ -24,6 +24,14 @@ func (c column) Expression() exp.Expression {
 	return goqu.T(c.table).Col(c.name).As(c.name)
 }
 
+// Alias returns the name of the column in the query result
+func (c column) Alias() string {
+	if c.alias != "" {
+		return c.alias
+	}
+	return c.name
+}
+
 type queryHelper struct {
 	*goqu.SelectDataset
 	table   exp.AliasedExpression
@@ -35,6 +43,9 @@ type queryHelper struct {
 type tableHelper struct {
 	table exp.AliasedExpression
 	alias string
+	// typeName is the concrete type stored in the table, it is set when querying abstract types stored in a
+	// table per type
+	typeName string
 }
 
 func (t tableHelper) Name() string {
@@ -51,6 +62,10 @@ func (q queryHelper) Table() tableHelper {
 }
 
 func (q queryHelper) TableName() string {
+	if f, ok := q.table.Aliased().(exp.SQLFunctionExpression); ok {
+		// rows selected from a set-returning function
+		return f.Name()
+	}
 	return q.table.Aliased().(exp.IdentifierExpression).GetTable()
 }
 
@@ -93,8 +108,12 @@ func (q queryHelper) buildJsonObject() exp.SQLFunctionExpression {
 			args[i*2] = goqu.L(fmt.Sprintf("'%s'", c.name))
 		}
 		if c.expression != nil {
-			args[i*2+1] = c.expression
-
+			// aliases aren't allowed inside jsonb_build_object
+			if aliased, ok := c.expression.(exp.AliasedExpression); ok {
+				args[i*2+1] = aliased.Aliased()
+			} else {
+				args[i*2+1] = c.expression
+			}
 		} else {
 			args[i*2+1] = goqu.I(fmt.Sprintf("%s.%s", c.table, c.name))
 		}
@@ -0,0 +1,73 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/schema"
+)
+
+// ReplicaPolicy defines how read queries are balanced between the read replicas of an Executor
+type ReplicaPolicy int
+
+const (
+	// RoundRobin sends each read query to the next replica in order
+	RoundRobin ReplicaPolicy = iota
+	// LeastConnections sends each read query to the replica with the least acquired connections
+	LeastConnections
+)
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose queries are sent to the primary database instead of a read replica, it's used
+// by flows that must read their own writes
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// WithReplicas sends the read queries of the executor to the given read replica pools, balanced by the policy.
+// Mutations are always executed on the primary pool the executor was created with, as are the queries of
+// mutation operations, of fields with a @primary directive and of contexts created by WithPrimary.
+func (e *Executor) WithReplicas(policy ReplicaPolicy, replicas ...*pgxpool.Pool) *Executor {
+	e.replicas = replicas
+	e.replicaPolicy = policy
+	return e
+}
+
+// readPool returns the pool the read queries of the context are sent to
+func (e *Executor) readPool(ctx context.Context) *pgxpool.Pool {
+	if len(e.replicas) == 0 || usePrimary(ctx) {
+		return e.pool
+	}
+	if e.replicaPolicy == LeastConnections {
+		pool := e.replicas[0]
+		for _, r := range e.replicas[1:] {
+			if r.Stat().AcquiredConns() < pool.Stat().AcquiredConns() {
+				pool = r
+			}
+		}
+		return pool
+	}
+	return e.replicas[(e.nextReplica.Add(1)-1)%uint64(len(e.replicas))]
+}
+
+// usePrimary returns true if the queries of the context must read from the primary database
+func usePrimary(ctx context.Context) bool {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return true
+	}
+	if graphql.HasOperationContext(ctx) {
+		if op := graphql.GetOperationContext(ctx).Operation; op != nil && op.Operation == ast.Mutation {
+			return true
+		}
+	}
+	for fc := graphql.GetFieldContext(ctx); fc != nil; fc = fc.Parent {
+		if fc.Field.Field != nil && fc.Field.Definition != nil && schema.IsPrimaryField(fc.Field.Definition) {
+			return true
+		}
+	}
+	return false
+}
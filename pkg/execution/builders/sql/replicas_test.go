@@ -0,0 +1,65 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	// pools connect lazily, no database is required
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:5432/postgres")
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestExecutor_readPool(t *testing.T) {
+	primary, replicaA, replicaB := newTestPool(t), newTestPool(t), newTestPool(t)
+	ctx := context.Background()
+
+	assert.Same(t, primary, NewExecutor(primary, &builders.Config{}).readPool(ctx))
+
+	e := NewExecutor(primary, &builders.Config{}).WithReplicas(RoundRobin, replicaA, replicaB)
+	assert.Same(t, replicaA, e.readPool(ctx))
+	assert.Same(t, replicaB, e.readPool(ctx))
+	assert.Same(t, replicaA, e.readPool(ctx))
+
+	leastConnections := NewExecutor(primary, &builders.Config{}).WithReplicas(LeastConnections, replicaA, replicaB)
+	assert.Same(t, replicaA, leastConnections.readPool(ctx))
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{
+			name: "with_primary",
+			ctx:  WithPrimary(ctx),
+		},
+		{
+			name: "mutation_operation",
+			ctx: graphql.WithOperationContext(ctx, &graphql.OperationContext{
+				Operation: &ast.OperationDefinition{Operation: ast.Mutation},
+			}),
+		},
+		{
+			name: "primary_field",
+			ctx: graphql.WithFieldContext(ctx, &graphql.FieldContext{Field: graphql.CollectedField{Field: &ast.Field{
+				Name:       "users",
+				Definition: &ast.FieldDefinition{Name: "users", Directives: ast.DirectiveList{{Name: "primary"}}},
+			}}}),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Same(t, primary, e.readPool(tc.ctx))
+		})
+	}
+}
@@ -59,6 +59,22 @@ func (t *TypeNameScanner[T]) ScanRow(row pgx.CollectableRow) (T, error) {
 	return v.(T), nil
 }
 
+// ScanMap decodes an already scanned value, i.e. a json object, into the appropriate concrete type based on the type discriminator.
+func (t *TypeNameScanner[T]) ScanMap(m map[string]any) (T, error) {
+	var value T
+	typeValue := strings.ToLower(fmt.Sprint(m[t.typeNameKey]))
+	valueType, ok := t.types[typeValue]
+	if !ok {
+		return value, fmt.Errorf("unknown type %s", typeValue)
+	}
+	// dynamically create a new instance of the struct type.
+	v := reflect.New(valueType).Interface()
+	if err := mapstructure.Decode(m, v); err != nil {
+		return value, err
+	}
+	return v.(T), nil
+}
+
 // ScanJson scans JSON data into the appropriate concrete type based on the type discriminator.
 func (t *TypeNameScanner[T]) ScanJson(data []byte) (T, error) {
 	var value T
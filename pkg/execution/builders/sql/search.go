@@ -0,0 +1,79 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+	"github.com/roneli/fastgql/pkg/schema"
+)
+
+// searchVector builds the full-text search document of the @searchable columns, a to_tsvector of the columns
+// concatenated with spaces, null columns are treated as empty text
+func (b Builder) searchVector(table exp.AliasedExpression, d *schema.SearchableDirective) exp.LiteralExpression {
+	parts := make([]string, 0, len(d.Columns))
+	args := make([]any, 0, len(d.Columns)+1)
+	args = append(args, d.Config)
+	for _, c := range d.Columns {
+		parts = append(parts, "coalesce(?, '')")
+		args = append(args, table.Col(b.CaseConverter(c)))
+	}
+	return goqu.L(fmt.Sprintf("to_tsvector(?::regconfig, %s)", strings.Join(parts, " || ' ' || ")), args...)
+}
+
+// searchQuery parses the user's search text using websearch syntax, i.e. "quoted phrases", or and -negation
+func searchQuery(d *schema.SearchableDirective, value any) exp.LiteralExpression {
+	return goqu.L("websearch_to_tsquery(?::regconfig, ?)", d.Config, value)
+}
+
+// buildSearchExp builds the full-text search match expression of a @searchable type
+func (b Builder) buildSearchExp(table exp.AliasedExpression, def *ast.Definition, value any) (goqu.Expression, error) {
+	d := schema.GetSearchableDirective(def)
+	if d == nil {
+		return nil, fmt.Errorf("%s is missing the @searchable directive", def.Name)
+	}
+	return goqu.L("? @@ ?", b.searchVector(table, d), searchQuery(d, value)), nil
+}
+
+// searchValue returns the search text of the field, taken from the search argument or the top level _search filter
+func searchValue(field builders.Field) (any, bool) {
+	if v, ok := field.Arguments[string(schema.Search)]; ok && v != nil {
+		return v, true
+	}
+	if filters, ok := field.Arguments["filter"].(map[string]any); ok {
+		if v, ok := filters[schema.SearchFilterFieldName]; ok && v != nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// buildSearch filters the query by the field's search argument
+func (b Builder) buildSearch(query *queryHelper, field builders.Field) error {
+	value, ok := field.Arguments[string(schema.Search)]
+	if !ok || value == nil {
+		return nil
+	}
+	b.Logger.Debug("adding search", "tableDefinition", query.TableName(), "search", value)
+	searchExp, err := b.buildSearchExp(query.table, field.TypeDefinition, value)
+	if err != nil {
+		return err
+	}
+	query.SelectDataset = query.Where(searchExp)
+	return nil
+}
+
+// buildSearchRank returns the ts_rank expression used to order by the field's search text, nil if there is no search text
+func (b Builder) buildSearchRank(query *queryHelper, field builders.Field) exp.Orderable {
+	d := schema.GetSearchableDirective(field.TypeDefinition)
+	value, ok := searchValue(field)
+	if d == nil || !ok {
+		b.Logger.Debug("skipping rank ordering without search", "tableDefinition", query.TableName())
+		return nil
+	}
+	return goqu.L("ts_rank(?, ?)", b.searchVector(query.table, d), searchQuery(d, value))
+}
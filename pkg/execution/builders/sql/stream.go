@@ -0,0 +1,118 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/roneli/fastgql/pkg/execution"
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+const (
+	defaultStreamChunkSize = 500
+	streamCursorName       = "fastgql_stream"
+)
+
+// txBeginner begins the read-only transactions of stream cursors, it's implemented by pgxpool.Pool
+type txBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// cursor is a server-side cursor of a streamed query, declared in a read-only transaction of its own that holds a
+// connection until the stream is closed
+type cursor struct {
+	tx pgx.Tx
+	// timeout is the statement timeout of each fetch, zero if it's unbounded
+	timeout time.Duration
+}
+
+// openCursor declares a cursor for the query in a new read-only transaction, the statements of the transaction are
+// bounded by the timeout
+func openCursor(ctx context.Context, db txBeginner, timeout time.Duration, query string, args ...any) (*cursor, error) {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		if _, err := tx.Exec(ctx, setLocalStatementTimeout(timeout)); err != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			return nil, err
+		}
+	}
+	if _, err := tx.Exec(ctx, declareCursor(query), args...); err != nil {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+		return nil, err
+	}
+	return &cursor{tx: tx, timeout: timeout}, nil
+}
+
+// fetch scans the next count rows of the cursor, the context's deadline is a backstop of the statement timeout
+func (c *cursor) fetch(ctx context.Context, count int, scan func(rows pgx.Rows) (int, error)) (int, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	rows, err := c.tx.Query(ctx, fetchCursor(count))
+	if err != nil {
+		return 0, err
+	}
+	return scan(rows)
+}
+
+// Close rolls back the cursor's read-only transaction, releasing its connection
+func (c *cursor) Close(ctx context.Context) error {
+	return c.tx.Rollback(ctx)
+}
+
+// stream scans the next items of the streamed field into dest, a pointer to a slice. A new stream declares a cursor
+// for the query and scans its first InitialCount rows, an opened stream scans the next Config.StreamChunkSize rows.
+// The stream is finished once its cursor returns fewer rows than requested or fails.
+func (e *Executor) stream(ctx context.Context, s *execution.Stream, dest any, query string, args []any, scan func(rows pgx.Rows) (int, error)) error {
+	return e.telemetry.execute(ctx, string(builders.QueryOperation), query, func(ctx context.Context) (int, error) {
+		c, ok := s.Cursor.(*cursor)
+		count := e.config.StreamChunkSize
+		if count <= 0 {
+			count = defaultStreamChunkSize
+		}
+		if !ok {
+			timeout := e.fieldStatementTimeout(ctx)
+			var err error
+			if c, err = openCursor(ctx, e.readPool(ctx), timeout, query, args...); err != nil {
+				return 0, queryError(ctx, timeout, err)
+			}
+			s.Open(c)
+			count = s.InitialCount
+		}
+		// FETCH FORWARD 0 fetches the current row again
+		if count == 0 {
+			v := reflect.ValueOf(dest).Elem()
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+			return 0, nil
+		}
+		n, err := c.fetch(ctx, count, scan)
+		if err != nil {
+			// the transaction of the cursor is aborted
+			s.Finish()
+			return n, queryError(ctx, c.timeout, err)
+		}
+		if n < count {
+			s.Finish()
+		}
+		return n, nil
+	})
+}
+
+// declareCursor returns the statement declaring the stream cursor of the query
+func declareCursor(query string) string {
+	return fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", streamCursorName, query)
+}
+
+// fetchCursor returns the statement fetching the next count rows of the stream cursor
+func fetchCursor(count int) string {
+	return fmt.Sprintf("FETCH FORWARD %d FROM %s", count, streamCursorName)
+}
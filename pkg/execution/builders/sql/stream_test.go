@@ -0,0 +1,49 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_openCursor(t *testing.T) {
+	query := `SELECT "name" FROM "users" WHERE "id" > $1`
+	scan := func(n int) func(pgx.Rows) (int, error) {
+		return func(pgx.Rows) (int, error) {
+			return n, nil
+		}
+	}
+
+	db := &fakeDatabase{}
+	c, err := openCursor(context.Background(), db, 1500*time.Millisecond, query, 1)
+	require.NoError(t, err)
+	n, err := c.fetch(context.Background(), 500, scan(500))
+	require.NoError(t, err)
+	assert.Equal(t, 500, n)
+	assert.WithinDuration(t, time.Now().Add(1500*time.Millisecond), db.deadline, time.Second, "fetches are bounded by the statement timeout")
+	require.NoError(t, c.Close(context.Background()))
+	assert.Equal(t, []string{
+		"BEGIN read only",
+		"SET LOCAL statement_timeout = 1500",
+		`DECLARE fastgql_stream NO SCROLL CURSOR FOR SELECT "name" FROM "users" WHERE "id" > $1`,
+		"FETCH FORWARD 500 FROM fastgql_stream",
+		"ROLLBACK",
+	}, db.statements)
+
+	// unbounded cursors don't set a statement timeout
+	db = &fakeDatabase{}
+	c, err = openCursor(context.Background(), db, 0, query, 1)
+	require.NoError(t, err)
+	_, err = c.fetch(context.Background(), 2, scan(1))
+	require.NoError(t, err)
+	assert.True(t, db.deadline.IsZero())
+	assert.Equal(t, []string{
+		"BEGIN read only",
+		`DECLARE fastgql_stream NO SCROLL CURSOR FOR SELECT "name" FROM "users" WHERE "id" > $1`,
+		"FETCH FORWARD 2 FROM fastgql_stream",
+	}, db.statements)
+}
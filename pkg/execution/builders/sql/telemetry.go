@@ -0,0 +1,129 @@
+package sql
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+const instrumentationName = "github.com/roneli/fastgql/pkg/execution/builders/sql"
+
+var (
+	// stringLiteralRegex matches single quoted SQL string literals, including escaped quotes
+	stringLiteralRegex = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// numericLiteralRegex matches numeric literals that are not part of an identifier or placeholder
+	numericLiteralRegex = regexp.MustCompile(`([^\w$."])(-?\d+(?:\.\d+)?)\b`)
+)
+
+// telemetry wraps the OpenTelemetry tracer and instruments used by the Executor.
+// When no providers are configured in builders.Config, noop implementations are used.
+type telemetry struct {
+	tracer        trace.Tracer
+	dbSystem      string
+	buildDuration metric.Float64Histogram
+	dbDuration    metric.Float64Histogram
+	resultSize    metric.Int64Histogram
+}
+
+func newTelemetry(config *builders.Config, dialect string) *telemetry {
+	var tp trace.TracerProvider = tracenoop.NewTracerProvider()
+	if config.TracerProvider != nil {
+		tp = config.TracerProvider
+	}
+	var mp metric.MeterProvider = metricnoop.NewMeterProvider()
+	if config.MeterProvider != nil {
+		mp = config.MeterProvider
+	}
+	meter := mp.Meter(instrumentationName)
+	// instrument creation only fails on invalid names, which are constant here
+	buildDuration, _ := meter.Float64Histogram("fastgql.build.duration",
+		metric.WithDescription("Time spent building queries from the GraphQL selection"), metric.WithUnit("s"))
+	dbDuration, _ := meter.Float64Histogram("fastgql.db.duration",
+		metric.WithDescription("Time spent executing queries and scanning results"), metric.WithUnit("s"))
+	resultSize, _ := meter.Int64Histogram("fastgql.result.size",
+		metric.WithDescription("Number of rows returned by a query"), metric.WithUnit("{row}"))
+	return &telemetry{
+		tracer:        tp.Tracer(instrumentationName),
+		dbSystem:      dbSystemName(dialect),
+		buildDuration: buildDuration,
+		dbDuration:    dbDuration,
+		resultSize:    resultSize,
+	}
+}
+
+// build records a span and duration histogram around query building
+func (t *telemetry) build(ctx context.Context, operation string, buildFn func() (string, []any, error)) (string, []any, error) {
+	attrs := t.attributes(ctx, operation)
+	ctx, span := t.tracer.Start(ctx, "fastgql.build", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	start := time.Now()
+	query, args, err := buildFn()
+	t.buildDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return query, args, err
+	}
+	span.SetAttributes(attribute.String("db.statement", sanitizeStatement(query)))
+	return query, args, nil
+}
+
+// execute records a span, duration and result size histograms around query execution,
+// execFn returns the number of rows scanned.
+func (t *telemetry) execute(ctx context.Context, operation, query string, execFn func(ctx context.Context) (int, error)) error {
+	attrs := t.attributes(ctx, operation)
+	ctx, span := t.tracer.Start(ctx, "fastgql.execute", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("db.statement", sanitizeStatement(query)))...))
+	defer span.End()
+
+	start := time.Now()
+	rows, err := execFn(ctx)
+	t.dbDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	span.SetAttributes(attribute.Int("db.response.returned_rows", rows))
+	t.resultSize.Record(ctx, int64(rows), metric.WithAttributes(attrs...))
+	return nil
+}
+
+func (t *telemetry) attributes(ctx context.Context, operation string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", t.dbSystem),
+		attribute.String("db.operation", operation),
+	}
+	if fc := graphql.GetFieldContext(ctx); fc != nil && fc.Field.Field != nil {
+		attrs = append(attrs, attribute.String("graphql.field.name", fc.Field.Name))
+	}
+	return attrs
+}
+
+// sanitizeStatement removes literal values from an SQL statement so it can be safely attached to spans.
+// Prepared placeholders ($1, $2...) are kept as is.
+func sanitizeStatement(query string) string {
+	query = stringLiteralRegex.ReplaceAllString(query, "?")
+	return numericLiteralRegex.ReplaceAllString(query, "$1?")
+}
+
+// dbSystemName maps a fastgql dialect name to the OpenTelemetry db.system value
+func dbSystemName(dialect string) string {
+	switch dialect {
+	case "postgres":
+		return "postgresql"
+	default:
+		return dialect
+	}
+}
@@ -0,0 +1,60 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/spf13/cast"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+	"github.com/roneli/fastgql/pkg/schema"
+)
+
+// temporalOperators are the operators of the DateComparator and DateTimeComparator, they are scoped to the scalar
+// type as their names may clash with other comparators, i.e. the PostGIS within operator.
+var temporalOperators = map[string]builders.Operator{
+	"olderThan": opOlderThan,
+	"within":    opWithinInterval,
+	"dayOfWeek": opDayOfWeek,
+	"year":      opYear,
+}
+
+// defaultScalarOperators are operators that apply only to fields of the given scalar type, they take precedence over
+// the operators of the same name in defaultOperators.
+var defaultScalarOperators = map[string]map[string]builders.Operator{
+	schema.DateScalarName:     temporalOperators,
+	schema.DateTimeScalarName: temporalOperators,
+}
+
+// intervalAgo returns now() minus the interval value, i.e. "3 days"
+func intervalAgo(value any) exp.LiteralExpression {
+	return goqu.L("now() - ?::interval", value)
+}
+
+func opBetween(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	r := cast.ToStringMap(value)
+	return table.Col(key).Between(goqu.Range(r["from"], r["to"]))
+}
+
+func opOlderThan(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return table.Col(key).Lt(intervalAgo(value))
+}
+
+func opWithinInterval(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return table.Col(key).Between(goqu.Range(intervalAgo(value), goqu.L("now()")))
+}
+
+func opDayOfWeek(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.L("EXTRACT(ISODOW FROM ?)", table.Col(key)).Eq(value)
+}
+
+func opYear(table exp.AliasedExpression, key string, value interface{}) goqu.Expression {
+	return goqu.L("EXTRACT(YEAR FROM ?)", table.Col(key)).Eq(value)
+}
+
+// timeBucket truncates a date or timestamp column to the bucket precision, i.e. hour, day, week or month.
+// The precision is inlined rather than a placeholder so the grouped and selected expressions are identical.
+func timeBucket(col exp.IdentifierExpression, bucket string) exp.LiteralExpression {
+	return goqu.L(fmt.Sprintf("date_trunc('%s', ?)", bucket), col)
+}
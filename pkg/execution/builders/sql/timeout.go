@@ -0,0 +1,81 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jackc/pgx/v5"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/schema"
+)
+
+type statementTimeoutKey struct{}
+
+// WithStatementTimeout returns a context whose queries are bounded by the timeout instead of the timeout of the
+// Config or of the type's @table directive, a zero timeout disables the statement timeout
+func WithStatementTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, statementTimeoutKey{}, timeout)
+}
+
+// statementTimeout returns the timeout of the queries of typeDef in the context, the context's timeout overrides the
+// type's @table(timeout:) which overrides Config.StatementTimeout
+func (e *Executor) statementTimeout(ctx context.Context, typeDef *ast.Definition) time.Duration {
+	if timeout, ok := ctx.Value(statementTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	if typeDef != nil {
+		if td, err := schema.GetTableDirective(typeDef); err == nil && td.Timeout > 0 {
+			return td.Timeout
+		}
+	}
+	return e.config.StatementTimeout
+}
+
+// fieldStatementTimeout returns the timeout of the queries of the context's field
+func (e *Executor) fieldStatementTimeout(ctx context.Context) time.Duration {
+	var typeDef *ast.Definition
+	if fc := graphql.GetFieldContext(ctx); fc != nil && fc.Field.Field != nil && fc.Field.Definition != nil && e.config.Schema != nil {
+		typeDef = e.config.Schema.Types[fc.Field.Definition.Type.Name()]
+	}
+	return e.statementTimeout(ctx, typeDef)
+}
+
+// database is the subset of pgxpool.Pool and pgx.Tx queries are executed with
+type database interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// withStatementTimeout runs fn bounded by the statement timeout. fn runs in a transaction of db whose statements are
+// bounded with SET LOCAL statement_timeout, so Postgres cancels them itself, and its context is canceled once the
+// timeout passed as a backstop if the server doesn't respond. Without a timeout fn runs on db as is.
+func withStatementTimeout(ctx context.Context, db database, timeout time.Duration, fn func(ctx context.Context, db database) (int, error)) (int, error) {
+	if timeout <= 0 {
+		return fn(ctx, db)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// rolling back a committed transaction does nothing
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+	if _, err := tx.Exec(ctx, setLocalStatementTimeout(timeout)); err != nil {
+		return 0, err
+	}
+	n, err := fn(ctx, tx)
+	if err != nil {
+		return n, err
+	}
+	return n, tx.Commit(ctx)
+}
+
+// setLocalStatementTimeout returns the statement bounding the statements of the current transaction by the timeout
+func setLocalStatementTimeout(timeout time.Duration) string {
+	return fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())
+}
@@ -0,0 +1,126 @@
+package sql
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+func TestExecutor_statementTimeout(t *testing.T) {
+	e := &Executor{config: &builders.Config{StatementTimeout: time.Second}}
+	report := &ast.Definition{Name: "Report", Directives: ast.DirectiveList{{
+		Name: "table",
+		Arguments: ast.ArgumentList{
+			{Name: "name", Value: &ast.Value{Kind: ast.StringValue, Raw: "reports"}},
+			{Name: "timeout", Value: &ast.Value{Kind: ast.StringValue, Raw: "30s"}},
+		},
+	}}}
+	user := &ast.Definition{Name: "User", Directives: ast.DirectiveList{{
+		Name:      "table",
+		Arguments: ast.ArgumentList{{Name: "name", Value: &ast.Value{Kind: ast.StringValue, Raw: "users"}}},
+	}}}
+
+	ctx := context.Background()
+	assert.Equal(t, time.Second, e.statementTimeout(ctx, nil))
+	assert.Equal(t, time.Second, e.statementTimeout(ctx, user))
+	assert.Equal(t, 30*time.Second, e.statementTimeout(ctx, report))
+	assert.Equal(t, 5*time.Second, e.statementTimeout(WithStatementTimeout(ctx, 5*time.Second), report))
+	assert.Equal(t, time.Duration(0), e.statementTimeout(WithStatementTimeout(ctx, 0), report))
+}
+
+// fakeDatabase records the statements executed on it and in its transactions
+type fakeDatabase struct {
+	statements []string
+	batch      *pgx.Batch
+	deadline   time.Time
+	results    *fakeBatchResults
+}
+
+func (f *fakeDatabase) Begin(context.Context) (pgx.Tx, error) {
+	f.statements = append(f.statements, "BEGIN")
+	return &fakeTx{db: f}, nil
+}
+
+func (f *fakeDatabase) BeginTx(_ context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	f.statements = append(f.statements, strings.TrimSpace("BEGIN "+string(opts.AccessMode)))
+	return &fakeTx{db: f}, nil
+}
+
+func (f *fakeDatabase) Query(ctx context.Context, sql string, _ ...any) (pgx.Rows, error) {
+	f.statements = append(f.statements, sql)
+	f.deadline, _ = ctx.Deadline()
+	return nil, nil
+}
+
+func (f *fakeDatabase) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	f.statements = append(f.statements, "BATCH")
+	f.batch = b
+	f.deadline, _ = ctx.Deadline()
+	return f.results
+}
+
+type fakeTx struct {
+	pgx.Tx
+	db     *fakeDatabase
+	closed bool
+}
+
+func (t *fakeTx) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	t.db.statements = append(t.db.statements, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (t *fakeTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.db.Query(ctx, sql, args...)
+}
+
+func (t *fakeTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return t.db.SendBatch(ctx, b)
+}
+
+func (t *fakeTx) Commit(context.Context) error {
+	t.db.statements = append(t.db.statements, "COMMIT")
+	t.closed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.db.statements = append(t.db.statements, "ROLLBACK")
+	t.closed = true
+	return nil
+}
+
+func Test_executeQuery(t *testing.T) {
+	query := `SELECT "name" FROM "reports"`
+	newQuery := func(timeout time.Duration) *batchedQuery {
+		return &batchedQuery{sql: query, timeout: timeout, scan: func(pgx.Rows) (int, error) {
+			return 1, nil
+		}}
+	}
+
+	// the statement timeout is set on the server, the context's deadline is a backstop
+	db := &fakeDatabase{}
+	_, err := executeQuery(context.Background(), db, newQuery(1500*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BEGIN", "SET LOCAL statement_timeout = 1500", query, "COMMIT"}, db.statements)
+	assert.WithinDuration(t, time.Now().Add(1500*time.Millisecond), db.deadline, time.Second)
+
+	// unbounded queries aren't executed in a transaction
+	db = &fakeDatabase{}
+	_, err = executeQuery(context.Background(), db, newQuery(0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{query}, db.statements)
+	assert.True(t, db.deadline.IsZero())
+}
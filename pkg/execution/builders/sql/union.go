@@ -0,0 +1,223 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+	"github.com/roneli/fastgql/pkg/schema"
+)
+
+// defaultTypeNameKey is the discriminator column projected by UNION ALL queries if the abstract type has no @typename
+const defaultTypeNameKey = "typename"
+
+// unionOrderPrefix prefixes the alias of columns projected by union members for distance and JSON path orderings,
+// apart from the selection of the same field
+const unionOrderPrefix = "_order_"
+
+// unionOrder is an ordering of the combined result of a union query, a nil exp orders by the search rank
+type unionOrder struct {
+	exp       exp.Orderable
+	orderType builders.OrderingTypes
+}
+
+// isTablePerType returns true if the field's type is abstract and each of its possible types is stored in its own
+// table: unions without a @table directive, or interfaces without a @typename discriminator column.
+// Otherwise, abstract types are stored in a single table discriminated by their @typename column.
+func isTablePerType(field builders.Field) bool {
+	if field.TypeDefinition == nil {
+		return false
+	}
+	switch field.TypeDefinition.Kind {
+	case ast.Union:
+		return field.TypeDefinition.Directives.ForName("table") == nil
+	case ast.Interface:
+		return field.TypeDefinition.Directives.ForName("typename") == nil
+	}
+	return false
+}
+
+// typeNameKey returns the discriminator column name of the abstract type
+func typeNameKey(def *ast.Definition) string {
+	if d := def.Directives.ForName("typename"); d != nil {
+		if name := d.Arguments.ForName("name"); name != nil {
+			return name.Value.Raw
+		}
+	}
+	return defaultTypeNameKey
+}
+
+// buildUnionQuery builds a UNION ALL query across the tables of the given member types. Each member subquery
+// projects the fields selected on the member (or on the abstract type itself) and a constant discriminator column
+// holding the lower cased member name, columns selected only by other members are padded with a NULL of the
+// column's type.
+// Filters are applied on each member, while ordering and pagination are applied on the combined result.
+func (b Builder) buildUnionQuery(field builders.Field, members []*ast.Definition) (*queryHelper, error) {
+	b.Logger.Debug("building union query", "type", field.TypeDefinition.Name)
+	if len(members) == 0 {
+		return nil, fmt.Errorf("%s has no member types", field.TypeDefinition.Name)
+	}
+	var filters map[string]any
+	if filterArg, ok := field.Arguments["filter"]; ok {
+		if filters, ok = filterArg.(map[string]any); !ok {
+			return nil, fmt.Errorf("unexpected filter arg type")
+		}
+	}
+	var orderFields []builders.OrderField
+	if orderBy, ok := field.Arguments["orderBy"]; ok {
+		var err error
+		if orderFields, err = builders.CollectOrdering(orderBy, field.OrderingDefinition(b.Schema)); err != nil {
+			return nil, err
+		}
+	}
+
+	key := typeNameKey(field.TypeDefinition)
+	memberQueries := make([]*queryHelper, 0, len(members))
+	aliases := make([]string, 0)
+	seen := map[string]struct{}{key: {}}
+	for _, member := range members {
+		memberField := field
+		memberField.TypeDefinition = member
+		memberField.Arguments = nil
+		memberField.Selections = make(builders.Fields, 0, len(field.Selections))
+		for _, s := range field.Selections {
+			if s.ObjectDefinition == nil || s.ObjectDefinition.Name == member.Name || s.ObjectDefinition.Name == field.TypeDefinition.Name {
+				memberField.Selections = append(memberField.Selections, s)
+			}
+		}
+		q, err := b.buildQuery(getTableName(b.Schema, member.Name, member.Name), memberField)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query for %s: %w", member.Name, err)
+		}
+		if filters != nil {
+			filterExp, err := b.buildFilterExp(tableHelper{table: q.table, alias: q.alias, typeName: member.Name}, field.TypeDefinition, filters)
+			if err != nil {
+				return nil, err
+			}
+			q.SelectDataset = q.Where(filterExp)
+		}
+		for _, c := range q.selects {
+			if _, ok := seen[c.Alias()]; ok {
+				continue
+			}
+			seen[c.Alias()] = struct{}{}
+			aliases = append(aliases, c.Alias())
+		}
+		memberQueries = append(memberQueries, q)
+	}
+	columns := make([]map[string]exp.Expression, len(memberQueries))
+	for i, q := range memberQueries {
+		columns[i] = make(map[string]exp.Expression, len(q.selects))
+		for _, c := range q.selects {
+			columns[i][c.Alias()] = c.Expression()
+		}
+	}
+	// ordering is applied on the combined result, so the ordered fields are projected by each member and the combined
+	// result is ordered by their aliases. Ordered fields that weren't selected are projected by the members, but not
+	// by the combined result, members that don't have the field are padded.
+	projected := aliases
+	orders := make([]unionOrder, 0, len(orderFields))
+	for _, o := range orderFields {
+		// search rank isn't a column, it can't be projected by the members
+		if o.Key == schema.SearchRankOrderingName {
+			orders = append(orders, unionOrder{orderType: o.Type})
+			continue
+		}
+		alias, name := o.Key, b.CaseConverter(o.Key)
+		var orderExp exp.Orderable = goqu.C(alias)
+		switch {
+		case o.Value != nil:
+			// distance and JSON path orderings need the column itself rather than its selection
+			alias = unionOrderPrefix + o.Key
+			orderExp = goqu.L("? <-> ?", goqu.C(alias), geoFromGeoJSON(o.Value))
+		case len(o.Path) > 0:
+			j, ok := schema.GetJSONPath(b.Schema, field.TypeDefinition, o.Key, o.Path)
+			if !ok {
+				continue
+			}
+			alias, name = unionOrderPrefix+o.Key, b.CaseConverter(j.Column())
+			orderExp = jsonPathValue(goqu.C(alias), j.Keys(), j.Leaf().Type.Name())
+		}
+		for i, q := range memberQueries {
+			if _, ok := columns[i][alias]; ok || members[i].Fields.ForName(o.Key) == nil {
+				continue
+			}
+			if d := computedField(members[i], o.Key); d != nil && alias == o.Key {
+				columns[i][alias] = computedExpression(q.table, d).As(alias)
+				continue
+			}
+			columns[i][alias] = q.table.Col(name).As(alias)
+		}
+		if _, ok := seen[alias]; !ok {
+			seen[alias] = struct{}{}
+			aliases = append(aliases, alias)
+		}
+		orders = append(orders, unionOrder{exp: orderExp, orderType: o.Type})
+	}
+	padding := b.unionPadding(field, memberQueries, columns, aliases)
+
+	var union *goqu.SelectDataset
+	for i, q := range memberQueries {
+		cols := make([]any, 0, len(aliases)+1)
+		cols = append(cols, goqu.L(fmt.Sprintf("'%s'", strings.ToLower(members[i].Name))).As(key))
+		for _, a := range aliases {
+			if c, ok := columns[i][a]; ok {
+				cols = append(cols, c)
+				continue
+			}
+			cols = append(cols, padding[a])
+		}
+		ds := q.Select(cols...).WithDialect(b.Dialect).Prepared(true)
+		if union == nil {
+			union = ds
+			continue
+		}
+		union = union.UnionAll(ds)
+	}
+
+	alias := b.TableNameGenerator.Generate(6)
+	table := goqu.T(alias).As(alias)
+	query := queryHelper{goqu.From(union.As(alias)), table, alias, nil, b.Dialect}
+	query.selects = append(query.selects, column{table: alias, name: key})
+	for _, a := range projected {
+		query.selects = append(query.selects, column{table: alias, name: a})
+	}
+	b.buildPagination(&query, field)
+	for _, o := range orders {
+		if o.exp == nil {
+			if o.exp = b.buildSearchRank(&query, field); o.exp == nil {
+				continue
+			}
+		}
+		b.appendOrdering(&query, o.exp, o.orderType)
+	}
+	return &query, nil
+}
+
+// unionPadding returns the typed NULL of each column, padding the members that don't project it. UNION resolves the
+// type of each column pairwise, so an untyped NULL of two members fails to unify with a third member's column.
+// Relations and aggregates are jsonb, other columns are typed by an empty subquery of the first member projecting them.
+func (b Builder) unionPadding(field builders.Field, memberQueries []*queryHelper, columns []map[string]exp.Expression, aliases []string) map[string]exp.Expression {
+	padding := make(map[string]exp.Expression, len(aliases))
+	for _, s := range field.Selections {
+		if s.FieldType == builders.TypeRelation || s.FieldType == builders.TypeAggregate {
+			padding[s.Name] = goqu.L("NULL::jsonb").As(s.Name)
+		}
+	}
+	for _, a := range aliases {
+		if _, ok := padding[a]; ok {
+			continue
+		}
+		for i, q := range memberQueries {
+			if c, ok := columns[i][a]; ok {
+				padding[a] = goqu.L("?", goqu.From(q.table).Select(c).Where(goqu.L("false"))).As(a)
+				break
+			}
+		}
+	}
+	return padding
+}
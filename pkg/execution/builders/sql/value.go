@@ -2,7 +2,14 @@ package sql
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/schema"
 )
 
 type wrappedValue struct {
@@ -13,6 +20,86 @@ func (w wrappedValue) Value() (driver.Value, error) {
 	return w.v, nil
 }
 
+// inputValue converts a mutation input value of the given field to the value written to the database,
+// Geometry and Geography values are converted from GeoJSON
+func inputValue(def *ast.Definition, fieldName string, value any) any {
+	if value != nil && isGeoField(def, fieldName) {
+		return geoFromGeoJSON(value)
+	}
+	return value
+}
+
+// jsonInputField returns the field of the definition if it's a typed JSON field, otherwise nil
+func jsonInputField(def *ast.Definition, fieldName string) *ast.FieldDefinition {
+	if def == nil {
+		return nil
+	}
+	f := def.Fields.ForName(fieldName)
+	if f == nil || schema.GetJSONDirective(f) == nil {
+		return nil
+	}
+	return f
+}
+
+// jsonInputValue converts the input of a typed JSON field to a jsonb value
+func jsonInputValue(value any) (exp.Expression, error) {
+	if value == nil {
+		return goqu.L("NULL"), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON input: %w", err)
+	}
+	return goqu.L("?::jsonb", string(data)), nil
+}
+
+// jsonUpdateValue converts the update input of a typed JSON field, {set: {...}} replaces the value of the column
+// and {merge: {...}} deep merges only the given keys into it. Lists of JSON objects are replaced.
+func jsonUpdateValue(col exp.Expression, field *ast.FieldDefinition, value any) (exp.Expression, error) {
+	if schema.IsListType(field.Type) {
+		return jsonInputValue(value)
+	}
+	update, ok := value.(map[string]any)
+	if !ok || len(update) != 1 {
+		return nil, fmt.Errorf("expected exactly one of set or merge for JSON field %s", field.Name)
+	}
+	if v, ok := update["set"]; ok {
+		return jsonInputValue(v)
+	}
+	merge, ok := update["merge"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected merge input map for JSON field %s got %T", field.Name, update["merge"])
+	}
+	return jsonMergeValue(col, merge)
+}
+
+// jsonMergeValue deep merges the keys of value into the JSON object, nested objects are merged recursively, i.e.
+// COALESCE(col, '{}'::jsonb) || jsonb_build_object('details', COALESCE(col->'details', '{}'::jsonb) || ...)
+func jsonMergeValue(base exp.Expression, value map[string]any) (exp.Expression, error) {
+	args := make([]any, 0, len(value)*2)
+	for _, k := range sortedKeys(value) {
+		if err := validatePath(k); err != nil {
+			return nil, fmt.Errorf("invalid JSON key %s: %w", k, err)
+		}
+		var v exp.Expression
+		var err error
+		switch kv := value[k].(type) {
+		case map[string]any:
+			v, err = jsonMergeValue(goqu.L(fmt.Sprintf("?->'%s'", k), base), kv)
+		case nil:
+			// keys explicitly given as null are set to a JSON null
+			v = goqu.L("'null'::jsonb")
+		default:
+			v, err = jsonInputValue(kv)
+		}
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, goqu.L(fmt.Sprintf("'%s'", k)), v)
+	}
+	return goqu.L("COALESCE(?, '{}'::jsonb) || ?", base, goqu.Func("jsonb_build_object", args...)), nil
+}
+
 func getInputValues(inputValues interface{}) ([]map[string]interface{}, error) {
 	switch v := inputValues.(type) {
 	case map[string]interface{}:
@@ -29,3 +116,21 @@ func getInputValues(inputValues interface{}) ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("unexpected value type %T", inputValues)
 	}
 }
+
+// castOperators are the operators whose values are of the compared field's scalar type
+var castOperators = map[string]bool{"eq": true, "neq": true, "gt": true, "gte": true, "lt": true, "lte": true, "in": true, "notIn": true}
+
+// castOperatorValue casts the value of the operator to the SQL type of the field's scalar, i.e. CAST($1 AS uuid)
+func castOperatorValue(operatorName string, value any, sqlType string) any {
+	if !castOperators[operatorName] || value == nil {
+		return value
+	}
+	if values, ok := value.([]any); ok {
+		casted := make([]any, len(values))
+		for i, v := range values {
+			casted[i] = goqu.Cast(goqu.V(v), sqlType)
+		}
+		return casted
+	}
+	return goqu.Cast(goqu.V(value), sqlType)
+}
@@ -3,8 +3,10 @@ package sql
 import (
 	"testing"
 
+	"github.com/doug-martin/goqu/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func TestWrappedValue_Value(t *testing.T) {
@@ -108,3 +110,57 @@ func TestGetInputValues_EmptyCases(t *testing.T) {
 		assert.Empty(t, got)
 	})
 }
+
+func TestJsonUpdateValue(t *testing.T) {
+	field := &ast.FieldDefinition{Name: "attributes", Type: ast.NamedType("Attributes", nil)}
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "set",
+			value: map[string]interface{}{"set": map[string]interface{}{"color": "red"}},
+			want:  `SELECT '{"color":"red"}'::jsonb`,
+		},
+		{
+			name:  "set_null",
+			value: map[string]interface{}{"set": nil},
+			want:  `SELECT NULL`,
+		},
+		{
+			name:  "merge",
+			value: map[string]interface{}{"merge": map[string]interface{}{"color": "red"}},
+			want:  `SELECT COALESCE("attributes", '{}'::jsonb) || jsonb_build_object('color', '"red"'::jsonb)`,
+		},
+		{
+			name:    "set_and_merge",
+			value:   map[string]interface{}{"set": map[string]interface{}{}, "merge": map[string]interface{}{}},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			value:   map[string]interface{}{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_key",
+			value:   map[string]interface{}{"merge": map[string]interface{}{"a'b": 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonUpdateValue(goqu.C("attributes"), field, tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			query, _, err := goqu.Dialect("postgres").Select(got).ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, query)
+		})
+	}
+}
@@ -3,12 +3,20 @@ package execution_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"os"
 	"strconv"
 	"testing"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
 
 	"github.com/roneli/fastgql/pkg/execution"
 	"github.com/roneli/fastgql/pkg/execution/__test__/graph"
@@ -16,6 +24,7 @@ import (
 	"github.com/roneli/fastgql/pkg/execution/builders"
 	"github.com/roneli/fastgql/pkg/execution/builders/sql"
 	"github.com/roneli/fastgql/pkg/execution/testhelpers"
+	"github.com/roneli/fastgql/pkg/schema"
 )
 
 // e2eTestCase defines a single e2e test case
@@ -409,3 +418,54 @@ func TestE2E_MutationSequence(t *testing.T) {
 	require.Len(t, verifyResult.Posts, 1)
 	assert.Equal(t, "Updated Post", verifyResult.Posts[0].Name)
 }
+
+// TestE2E_EnumFilter tests filtering enum and enum array columns, list operator values must be typed as the column's
+// enum array rather than a text array.
+func TestE2E_EnumFilter(t *testing.T) {
+	ctx := context.Background()
+	pool, cleanup, err := testhelpers.GetTestPostgresPool(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	data, err := os.ReadFile("builders/sql/testdata/schema_enum.graphql")
+	require.NoError(t, err)
+	testSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: string(data)})
+	require.NoError(t, err)
+	src, err := schema.NewFastGQLPlugin("", "", false).CreateAugmented(testSchema)
+	require.NoError(t, err)
+	augmentedSchema, err := gqlparser.LoadSchema(src...)
+	require.NoError(t, err)
+	builder := sql.NewBuilder(&builders.Config{Schema: augmentedSchema})
+
+	tests := []struct {
+		name     string
+		filter   string
+		expected []string
+	}{
+		{name: "in", filter: `{status: {in: [CLOSED]}}`, expected: []string{"triage", "question"}},
+		{name: "list_eq", filter: `{labels: {eq: [BUG, FEATURE]}}`, expected: []string{"triage"}},
+		{name: "list_contains", filter: `{labels: {contains: [BUG]}}`, expected: []string{"crash", "triage"}},
+		{name: "list_contained_by", filter: `{labels: {containedBy: [BUG]}}`, expected: []string{"crash", "question"}},
+		{name: "list_overlap", filter: `{labels: {overlap: [FEATURE]}}`, expected: []string{"dark mode", "triage"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := fmt.Sprintf(`query { tickets(filter: %s) { name } }`, tt.filter)
+			doc, err := parser.ParseQuery(&ast.Source{Input: query})
+			require.NoError(t, err)
+			require.Nil(t, validator.ValidateWithRules(augmentedSchema, doc, nil))
+			sel := doc.Operations[0].SelectionSet[0].(*ast.Field)
+			opCtx := &graphql.OperationContext{RawQuery: query, Variables: map[string]any{}, Doc: doc}
+			field, err := builders.CollectFromQuery(sel, augmentedSchema, opCtx, sel.ArgumentMap(nil))
+			require.NoError(t, err)
+			sqlQuery, args, err := builder.Query(field)
+			require.NoError(t, err)
+
+			rows, err := pool.Query(ctx, sqlQuery, args...)
+			require.NoError(t, err)
+			names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, names)
+		})
+	}
+}
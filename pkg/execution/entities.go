@@ -0,0 +1,95 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+// EntityExecutor is implemented by executors that can resolve Apollo Federation entities, it is used by the
+// generated entity resolvers of @key types.
+type EntityExecutor interface {
+	// Entities resolves the typeName entities of the given key values with a single query, dest is a pointer to a
+	// slice of the entity type and is filled in the same order as keys
+	Entities(ctx context.Context, typeName string, keyFields []string, keys [][]any, dest any) error
+}
+
+// Entities resolves the typeName entities represented by keys using the executor, the executor must implement
+// EntityExecutor. Each key holds a value for each of the keyFields, entities that aren't found are left nil.
+func Entities(ctx context.Context, executor Executor, typeName string, keyFields []string, keys [][]any, dest any) error {
+	entityExecutor, ok := executor.(EntityExecutor)
+	if !ok {
+		return builders.GQLError(fmt.Errorf("executor %T does not support resolving entities", executor))
+	}
+	return builders.GQLError(entityExecutor.Entities(ctx, typeName, keyFields, keys, dest))
+}
+
+// Entities resolves the typeName entities of the given key values with a single batched query against the executor
+// of the type's dialect, honoring the selection set of the _entities field including relations.
+func (m *MultiExecutor) Entities(ctx context.Context, typeName string, keyFields []string, keys [][]any, dest any) error {
+	typeDef := m.schema.Types[typeName]
+	if typeDef == nil {
+		return fmt.Errorf("entity type %s not found", typeName)
+	}
+	dialect := m.getDialectForType(typeDef)
+	executor, ok := m.executors[dialect]
+	if !ok {
+		return builders.InternalErrorf("no executor registered for dialect: %s", dialect)
+	}
+	fieldExecutor, ok := executor.(FieldExecutor)
+	if !ok {
+		return fmt.Errorf("executor for dialect %s does not support resolving entities", dialect)
+	}
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("entities destination must be a pointer to a slice, got %T", dest)
+	}
+
+	// collect distinct keys
+	keyFilter := &builders.KeyFilter{Fields: keyFields}
+	seen := make(map[string]struct{}, len(keys))
+	for _, values := range keys {
+		k := keyString(values)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		keyFilter.Values = append(keyFilter.Values, values)
+	}
+	sliceType := destVal.Elem().Type()
+	entities := reflect.MakeSlice(sliceType, len(keys), len(keys))
+	if len(keyFilter.Values) > 0 {
+		field, err := builders.CollectEntityFields(ctx, m.schema, typeName)
+		if err != nil {
+			return err
+		}
+		results := reflect.New(sliceType)
+		if err := fieldExecutor.QueryField(ctx, batchField(field, keyFilter), results.Interface()); err != nil {
+			return err
+		}
+		if err := m.resolveRemoteRelations(ctx, field, flatten(results.Elem())); err != nil {
+			return err
+		}
+		// match results to their keys, entities must be returned in the order of their representations
+		byKey := make(map[string]reflect.Value, results.Elem().Len())
+		for i := 0; i < results.Elem().Len(); i++ {
+			entity := results.Elem().Index(i)
+			values, err := keyValues(entity, keyFields)
+			if err != nil {
+				return err
+			}
+			if values != nil {
+				byKey[keyString(values)] = entity
+			}
+		}
+		for i, values := range keys {
+			if entity, ok := byKey[keyString(values)]; ok {
+				entities.Index(i).Set(entity)
+			}
+		}
+	}
+	destVal.Elem().Set(entities)
+	return nil
+}
@@ -0,0 +1,73 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+func entitiesContext(t *testing.T, s *ast.Schema, query string) context.Context {
+	doc, errs := gqlparser.LoadQuery(s, query)
+	require.Nil(t, errs)
+	field := doc.Operations[0].SelectionSet[0].(*ast.Field)
+	ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{Doc: doc, Variables: map[string]any{}})
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{Field: graphql.CollectedField{Field: field}})
+}
+
+func TestMultiExecutor_Entities(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		directive @table(name: String!, dialect: String! = "postgres", schema: String = "") on OBJECT
+		scalar _Any
+		type Activity @table(name: "activities", dialect: "mongo") {
+			id: String!
+			userId: Int
+		}
+		type User @table(name: "users") {
+			id: Int!
+		}
+		union _Entity = Activity | User
+		type Query {
+			_entities(representations: [_Any!]!): [_Entity]!
+		}
+	`})
+	ctx := entitiesContext(t, s, `query { _entities(representations: []) { ... on Activity { userId } ... on User { id } } }`)
+	one, two := 1, 2
+	mongoExec := &mockFieldExecutor{results: []*testActivity{{ID: "b", UserID: &two}, {ID: "a", UserID: &one}}}
+	multi := NewMultiExecutor(s, "postgres")
+	multi.Register("mongo", mongoExec)
+
+	var activities []*testActivity
+	err := multi.Entities(ctx, "Activity", []string{"id"}, [][]any{{"a"}, {"c"}, {"b"}, {"a"}}, &activities)
+	require.NoError(t, err)
+
+	// a single batched query with distinct keys, selecting the fields of the Activity fragment and the key field
+	require.Len(t, mongoExec.fields, 1)
+	batch := mongoExec.fields[0]
+	assert.Equal(t, &builders.KeyFilter{Fields: []string{"id"}, Values: [][]any{{"a"}, {"c"}, {"b"}}}, batch.KeyFilter)
+	assert.Equal(t, "Activity", batch.TypeDefinition.Name)
+	assert.True(t, batch.Selections.HasSelection("userId"))
+	assert.True(t, batch.Selections.HasSelection("id"))
+
+	// entities are returned in the order of their keys, missing entities are nil
+	require.Len(t, activities, 4)
+	assert.Equal(t, "a", activities[0].ID)
+	assert.Nil(t, activities[1])
+	assert.Equal(t, "b", activities[2].ID)
+	assert.Equal(t, "a", activities[3].ID)
+
+	t.Run("executor_without_entities_support", func(t *testing.T) {
+		err := Entities(ctx, &mockExecutor{}, "Activity", []string{"id"}, nil, &activities)
+		assert.ErrorContains(t, err, "does not support resolving entities")
+	})
+	t.Run("unknown_type", func(t *testing.T) {
+		err := Entities(ctx, multi, "Missing", []string{"id"}, nil, &activities)
+		assert.ErrorContains(t, err, "entity type Missing not found")
+	})
+}
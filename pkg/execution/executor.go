@@ -2,7 +2,6 @@ package execution
 
 import (
 	"context"
-	"fmt"
 	"reflect"
 
 	"github.com/roneli/fastgql/pkg/execution/builders"
@@ -20,8 +19,27 @@ type Executor interface {
 	Mutate(ctx context.Context, dest any) error
 }
 
+// TypedMutator is implemented by executors that can execute mutations whose payload returns interface types, the
+// generated resolvers of mutations on interfaces use it through MutateWithTypes.
+type TypedMutator interface {
+	// MutateWithTypes executes a mutation whose payload returns interface types that need type discrimination
+	MutateWithTypes(ctx context.Context, dest any, types map[string]reflect.Type, typeKey string) error
+}
+
+// MutateWithTypes executes the mutation in ctx using the executor, which must implement TypedMutator, and scans
+// the returned objects into their concrete type based on the typeKey discriminator.
+func MutateWithTypes(ctx context.Context, executor Executor, dest any, types map[string]reflect.Type, typeKey string) error {
+	typedMutator, ok := executor.(TypedMutator)
+	if !ok {
+		return builders.GQLError(builders.InternalErrorf("executor %T does not support mutations on interfaces", executor))
+	}
+	return typedMutator.MutateWithTypes(ctx, dest, types, typeKey)
+}
+
 // MultiExecutor routes queries to the appropriate executor based on the type's dialect.
 // It reads the dialect from the @table directive on each GraphQL type.
+// Relations whose type resides in a different dialect than their parent are resolved in a second batched query
+// against the relation's executor, which must implement FieldExecutor.
 type MultiExecutor struct {
 	executors      map[string]Executor
 	schema         *ast.Schema
@@ -44,43 +62,74 @@ func (m *MultiExecutor) Register(dialect string, executor Executor) {
 
 // Query routes the query to the appropriate executor based on the type's dialect.
 func (m *MultiExecutor) Query(ctx context.Context, dest any) error {
-	field := builders.CollectFields(ctx, m.schema)
+	field, err := builders.CollectFields(ctx, m.schema)
+	if err != nil {
+		return builders.GQLError(err)
+	}
 	dialect := m.getDialectForType(field.TypeDefinition)
 
 	executor, ok := m.executors[dialect]
 	if !ok {
-		return fmt.Errorf("no executor registered for dialect: %s", dialect)
+		return builders.GQLError(builders.InternalErrorf("no executor registered for dialect: %s", dialect))
 	}
 
-	return executor.Query(ctx, dest)
+	if err := executor.Query(ctx, dest); err != nil {
+		return err
+	}
+	return builders.GQLError(m.resolveRemoteRelations(ctx, field, flatten(reflect.ValueOf(dest))))
 }
 
 // QueryWithTypes routes the query to the appropriate executor for interface types.
 func (m *MultiExecutor) QueryWithTypes(ctx context.Context, dest any, types map[string]reflect.Type, typeKey string) error {
-	field := builders.CollectFields(ctx, m.schema)
+	field, err := builders.CollectFields(ctx, m.schema)
+	if err != nil {
+		return builders.GQLError(err)
+	}
 	dialect := m.getDialectForType(field.TypeDefinition)
 
 	executor, ok := m.executors[dialect]
 	if !ok {
-		return fmt.Errorf("no executor registered for dialect: %s", dialect)
+		return builders.GQLError(builders.InternalErrorf("no executor registered for dialect: %s", dialect))
 	}
 
-	return executor.QueryWithTypes(ctx, dest, types, typeKey)
+	if err := executor.QueryWithTypes(ctx, dest, types, typeKey); err != nil {
+		return err
+	}
+	return builders.GQLError(m.resolveRemoteRelations(ctx, field, flatten(reflect.ValueOf(dest))))
 }
 
 // Mutate routes the mutation to the appropriate executor based on the type's dialect.
 func (m *MultiExecutor) Mutate(ctx context.Context, dest any) error {
-	field := builders.CollectFields(ctx, m.schema)
+	field, err := builders.CollectFields(ctx, m.schema)
+	if err != nil {
+		return builders.GQLError(err)
+	}
 	dialect := m.getDialectForType(field.TypeDefinition)
 
 	executor, ok := m.executors[dialect]
 	if !ok {
-		return fmt.Errorf("no executor registered for dialect: %s", dialect)
+		return builders.GQLError(builders.InternalErrorf("no executor registered for dialect: %s", dialect))
 	}
 
 	return executor.Mutate(ctx, dest)
 }
 
+// MutateWithTypes routes the mutation to the appropriate executor for payloads returning interface types.
+func (m *MultiExecutor) MutateWithTypes(ctx context.Context, dest any, types map[string]reflect.Type, typeKey string) error {
+	field, err := builders.CollectFields(ctx, m.schema)
+	if err != nil {
+		return builders.GQLError(err)
+	}
+	dialect := m.getDialectForType(field.TypeDefinition)
+
+	executor, ok := m.executors[dialect]
+	if !ok {
+		return builders.GQLError(builders.InternalErrorf("no executor registered for dialect: %s", dialect))
+	}
+
+	return MutateWithTypes(ctx, executor, dest, types, typeKey)
+}
+
 // getDialectForType extracts the dialect from the @table directive on a type.
 func (m *MultiExecutor) getDialectForType(typeDef *ast.Definition) string {
 	if typeDef == nil {
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/roneli/fastgql/pkg/execution/builders"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -154,3 +156,156 @@ func (m *mockExecutor) QueryWithTypes(_ context.Context, _ any, _ map[string]ref
 func (m *mockExecutor) Mutate(_ context.Context, _ any) error {
 	return nil
 }
+
+// mockTypedMutator implements TypedMutator, recording the typeKey it was called with
+type mockTypedMutator struct {
+	mockExecutor
+	typeKey string
+}
+
+func (m *mockTypedMutator) MutateWithTypes(_ context.Context, _ any, _ map[string]reflect.Type, typeKey string) error {
+	m.typeKey = typeKey
+	return nil
+}
+
+func TestMutateWithTypes(t *testing.T) {
+	typed := &mockTypedMutator{}
+	require.NoError(t, MutateWithTypes(context.Background(), typed, nil, nil, "type"))
+	assert.Equal(t, "type", typed.typeKey)
+
+	err := MutateWithTypes(context.Background(), &mockExecutor{}, nil, nil, "type")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support mutations on interfaces")
+}
+
+// mockFieldExecutor implements FieldExecutor, returning the given results, a slice of the field's type, for any field
+type mockFieldExecutor struct {
+	mockExecutor
+	results any
+	fields  []builders.Field
+}
+
+func (m *mockFieldExecutor) QueryField(_ context.Context, field builders.Field, dest any) error {
+	m.fields = append(m.fields, field)
+	reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(m.results))
+	return nil
+}
+
+type testActivity struct {
+	ID     string `json:"id" db:"id"`
+	UserID *int   `json:"userId" db:"user_id"`
+}
+
+type testUser struct {
+	ID         int             `json:"id" db:"id"`
+	Activities []*testActivity `json:"activities" db:"activities"`
+}
+
+// testDevice and testOwner are related by keys scanned into different Go types
+type testDevice struct {
+	ID      int64   `json:"id" db:"id"`
+	OwnerID *string `json:"ownerId" db:"owner_id"`
+	ModelID int64   `json:"modelId" db:"model_id"`
+}
+
+type testOwner struct {
+	ID      pgtype.UUID   `json:"id" db:"id"`
+	ModelID int32         `json:"modelId" db:"model_id"`
+	Devices []*testDevice `json:"devices" db:"devices"`
+}
+
+func TestMultiExecutor_resolveRemoteRelations(t *testing.T) {
+	activityDef := &ast.Definition{
+		Name: "Activity",
+		Directives: ast.DirectiveList{{Name: "table", Arguments: ast.ArgumentList{
+			{Name: "name", Value: &ast.Value{Raw: "activities"}},
+			{Name: "dialect", Value: &ast.Value{Raw: "mongo"}},
+		}}},
+	}
+	relationDef := &ast.FieldDefinition{
+		Name: "activities",
+		Directives: ast.DirectiveList{{Name: "relation", Arguments: ast.ArgumentList{
+			{Name: "type", Value: &ast.Value{Raw: "ONE_TO_MANY", Kind: ast.EnumValue}},
+			{Name: "fields", Value: &ast.Value{Kind: ast.ListValue, Children: ast.ChildValueList{{Value: &ast.Value{Raw: "id", Kind: ast.StringValue}}}}},
+			{Name: "references", Value: &ast.Value{Kind: ast.ListValue, Children: ast.ChildValueList{{Value: &ast.Value{Raw: "user_id", Kind: ast.StringValue}}}}},
+		}}},
+	}
+	field := builders.Field{
+		RemoteRelations: builders.Fields{{
+			Field:          &ast.Field{Name: "activities", Alias: "activities", Definition: relationDef},
+			FieldType:      builders.TypeRelation,
+			TypeDefinition: activityDef,
+			Arguments:      map[string]any{"limit": int64(1), "offset": int64(0)},
+			Selections:     builders.Fields{{Field: &ast.Field{Name: "id", Alias: "id"}, FieldType: builders.TypeScalar}},
+		}},
+	}
+	one, two := 1, 2
+	mongoExec := &mockFieldExecutor{results: []*testActivity{
+		{ID: "a", UserID: &one}, {ID: "b", UserID: &one}, {ID: "c", UserID: &two},
+	}}
+	multi := NewMultiExecutor(&ast.Schema{}, "postgres")
+	multi.Register("mongo", mongoExec)
+
+	users := []*testUser{{ID: 1}, {ID: 2}, {ID: 1}, {ID: 3}}
+	err := multi.resolveRemoteRelations(context.Background(), field, flatten(reflect.ValueOf(&users)))
+	require.NoError(t, err)
+
+	// a single batched query with distinct parent keys and the reference field added
+	require.Len(t, mongoExec.fields, 1)
+	batch := mongoExec.fields[0]
+	assert.Equal(t, &builders.KeyFilter{Columns: []string{"user_id"}, Values: [][]any{{1}, {2}, {3}}}, batch.KeyFilter)
+	assert.True(t, batch.Selections.HasSelection("userId"))
+	assert.NotContains(t, batch.Arguments, "limit")
+
+	// results stitched per parent with limit applied per parent
+	require.Len(t, users[0].Activities, 1)
+	assert.Equal(t, "a", users[0].Activities[0].ID)
+	require.Len(t, users[1].Activities, 1)
+	assert.Equal(t, "c", users[1].Activities[0].ID)
+	require.Len(t, users[2].Activities, 1)
+	assert.Empty(t, users[3].Activities)
+
+	t.Run("mismatched_key_types", func(t *testing.T) {
+		keys := func(names ...string) *ast.Value {
+			v := &ast.Value{Kind: ast.ListValue}
+			for _, n := range names {
+				v.Children = append(v.Children, &ast.ChildValue{Value: &ast.Value{Raw: n, Kind: ast.StringValue}})
+			}
+			return v
+		}
+		field := builders.Field{
+			RemoteRelations: builders.Fields{{
+				Field: &ast.Field{Name: "devices", Alias: "devices", Definition: &ast.FieldDefinition{
+					Name: "devices",
+					Directives: ast.DirectiveList{{Name: "relation", Arguments: ast.ArgumentList{
+						{Name: "type", Value: &ast.Value{Raw: "ONE_TO_MANY", Kind: ast.EnumValue}},
+						{Name: "fields", Value: keys("id", "model_id")},
+						{Name: "references", Value: keys("owner_id", "model_id")},
+					}}},
+				}},
+				FieldType:      builders.TypeRelation,
+				TypeDefinition: activityDef,
+				Selections:     builders.Fields{{Field: &ast.Field{Name: "id", Alias: "id"}, FieldType: builders.TypeScalar}},
+			}},
+		}
+		id := [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+		upper, other := "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+		devicesExec := &mockFieldExecutor{results: []*testDevice{
+			{ID: 1, OwnerID: &upper, ModelID: 7}, {ID: 2, OwnerID: &upper, ModelID: 8}, {ID: 3, OwnerID: &other, ModelID: 7},
+		}}
+		multi.Register("mongo", devicesExec)
+
+		owners := []*testOwner{{ID: pgtype.UUID{Bytes: id, Valid: true}, ModelID: 7}, {ID: pgtype.UUID{}, ModelID: 7}}
+		err := multi.resolveRemoteRelations(context.Background(), field, flatten(reflect.ValueOf(&owners)))
+		require.NoError(t, err)
+		require.Len(t, owners[0].Devices, 1)
+		assert.Equal(t, int64(1), owners[0].Devices[0].ID)
+		assert.Empty(t, owners[1].Devices, "NULL keys have no related objects")
+	})
+
+	t.Run("executor_without_field_support", func(t *testing.T) {
+		multi.Register("mongo", &mockExecutor{dialect: "mongo"})
+		err := multi.resolveRemoteRelations(context.Background(), field, flatten(reflect.ValueOf(&users)))
+		assert.ErrorContains(t, err, "does not support resolving relations")
+	})
+}
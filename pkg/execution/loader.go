@@ -0,0 +1,222 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+const (
+	// DefaultLoaderWait is the time a Loader waits for more keys before dispatching a batch
+	DefaultLoaderWait = time.Millisecond
+	// DefaultLoaderMaxBatch is the maximum number of keys dispatched in a single batch
+	DefaultLoaderMaxBatch = 1000
+	// DefaultLoaderTimeout is the time a batch is given to resolve
+	DefaultLoaderTimeout = 30 * time.Second
+)
+
+type loadersKey struct{}
+
+// BatchFunc resolves values for a batch of keys, the returned values must have the same length and order as keys.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) ([]V, error)
+
+// Loader batches and caches Load calls, it is used by resolvers of @fastgqlField(skipSelect) fields so sibling
+// objects are resolved in a single lookup instead of one lookup per object (N+1).
+// A Loader should live for a single request, see LoadersMiddleware and Load.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+	timeout  time.Duration
+
+	mu    sync.Mutex
+	cache map[K]*loaderResult[V]
+	batch *loaderBatch[K, V]
+}
+
+type loaderResult[V any] struct {
+	value V
+	err   error
+	done  chan struct{}
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loaderResult[V]
+	closed  bool
+}
+
+// NewLoader creates a new Loader, keys loaded within wait of each other are dispatched to fetch in a single batch
+// of at most maxBatch keys. A maxBatch of zero or less means batches are not limited in size.
+// Batches are shared by the Load calls of many resolvers, so they aren't canceled with the context of the call that
+// dispatched them, instead each batch is bounded by timeout. A timeout of zero or less means batches are not bounded.
+func NewLoader[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int, timeout time.Duration) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		timeout:  timeout,
+		cache:    make(map[K]*loaderResult[V]),
+	}
+}
+
+// Load returns the value for the given key, blocking until the batch holding the key is resolved or ctx is done
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	if r, ok := l.cache[key]; ok {
+		l.mu.Unlock()
+		return r.wait(ctx)
+	}
+	r := &loaderResult[V]{done: make(chan struct{})}
+	l.cache[key] = r
+	if l.batch == nil {
+		l.batch = &loaderBatch[K, V]{}
+		go l.dispatchAfter(ctx, l.batch)
+	}
+	b := l.batch
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, r)
+	if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+		l.batch = nil
+		b.closed = true
+		l.mu.Unlock()
+		go l.dispatch(ctx, b)
+	} else {
+		l.mu.Unlock()
+	}
+	return r.wait(ctx)
+}
+
+// wait blocks until the result is resolved or ctx is done, the result is still resolved for other callers
+func (r *loaderResult[V]) wait(ctx context.Context) (V, error) {
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *Loader[K, V]) dispatchAfter(ctx context.Context, b *loaderBatch[K, V]) {
+	time.Sleep(l.wait)
+	l.mu.Lock()
+	if b.closed {
+		// batch was already dispatched when it reached its max size
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	b.closed = true
+	l.mu.Unlock()
+	l.dispatch(ctx, b)
+}
+
+// dispatch fetches the batch and resolves each of its results, results failed by a canceled or timed out fetch are
+// removed from the cache so the keys are fetched again by later calls
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *loaderBatch[K, V]) {
+	ctx = context.WithoutCancel(ctx)
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+	values, err := l.fetchBatch(ctx, b.keys)
+	if err == nil && len(values) != len(b.keys) {
+		err = fmt.Errorf("batch function returned %d values for %d keys", len(values), len(b.keys))
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		l.mu.Lock()
+		for i, k := range b.keys {
+			if l.cache[k] == b.results[i] {
+				delete(l.cache, k)
+			}
+		}
+		l.mu.Unlock()
+	}
+	for i, r := range b.results {
+		if err != nil {
+			r.err = err
+		} else {
+			r.value = values[i]
+		}
+		close(r.done)
+	}
+}
+
+// fetchBatch calls the batch function, a panic is returned as an error so every result of the batch is resolved
+func (l *Loader[K, V]) fetchBatch(ctx context.Context, keys []K) (values []V, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			values, err = nil, fmt.Errorf("batch function panicked: %v", p)
+		}
+	}()
+	return l.fetch(ctx, keys)
+}
+
+// loaders holds the request scoped loaders by name
+type loaders struct {
+	mu sync.Mutex
+	m  map[string]any
+}
+
+// WithLoaders returns a context that holds request scoped loaders used by Load
+func WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{m: make(map[string]any)})
+}
+
+// LoadersMiddleware adds request scoped loaders to each operation, it should be added to the server using
+// handler.Server.AroundOperations
+func LoadersMiddleware(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(WithLoaders(ctx))
+}
+
+// Load loads the key using the request scoped loader registered under name, creating it with fetch if it doesn't
+// exist yet. If the context has no loaders (see WithLoaders), fetch is called directly with the single key.
+func Load[K comparable, V any](ctx context.Context, name string, key K, fetch BatchFunc[K, V]) (V, error) {
+	ls, ok := ctx.Value(loadersKey{}).(*loaders)
+	if !ok {
+		var zero V
+		values, err := fetch(ctx, []K{key})
+		if err != nil {
+			return zero, err
+		}
+		if len(values) != 1 {
+			return zero, fmt.Errorf("batch function returned %d values for 1 key", len(values))
+		}
+		return values[0], nil
+	}
+	ls.mu.Lock()
+	l, ok := ls.m[name]
+	if !ok {
+		l = NewLoader(fetch, DefaultLoaderWait, DefaultLoaderMaxBatch, DefaultLoaderTimeout)
+		ls.m[name] = l
+	}
+	ls.mu.Unlock()
+	loader, ok := l.(*Loader[K, V])
+	if !ok {
+		var zero V
+		return zero, fmt.Errorf("loader %s was registered with a different type %T", name, l)
+	}
+	return loader.Load(ctx, key)
+}
+
+// CompositeKey creates a comparable key out of multiple key values, pointer values are dereferenced
+func CompositeKey(values ...any) string {
+	deref := make([]any, len(values))
+	for i, v := range values {
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if rv.IsValid() && rv.Kind() != reflect.Ptr {
+			deref[i] = rv.Interface()
+		}
+	}
+	return keyString(deref)
+}
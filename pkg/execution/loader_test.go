@@ -0,0 +1,211 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Load(t *testing.T) {
+	tests := []struct {
+		name            string
+		keys            []int
+		maxBatch        int
+		fetchErr        error
+		expectedBatches int
+		expectedErr     string
+	}{
+		{
+			name:            "single_batch",
+			keys:            []int{1, 2, 3, 4},
+			expectedBatches: 1,
+		},
+		{
+			name:            "duplicate_keys_loaded_once",
+			keys:            []int{1, 1, 2, 2},
+			expectedBatches: 1,
+		},
+		{
+			name:            "max_batch",
+			keys:            []int{1, 2, 3, 4},
+			maxBatch:        2,
+			expectedBatches: 2,
+		},
+		{
+			name:            "fetch_error",
+			keys:            []int{1, 2},
+			fetchErr:        errors.New("failed"),
+			expectedBatches: 1,
+			expectedErr:     "failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				batches [][]int
+			)
+			loader := NewLoader(func(ctx context.Context, keys []int) ([]string, error) {
+				mu.Lock()
+				batches = append(batches, keys)
+				mu.Unlock()
+				if tt.fetchErr != nil {
+					return nil, tt.fetchErr
+				}
+				values := make([]string, len(keys))
+				for i, k := range keys {
+					values[i] = fmt.Sprintf("value-%d", k)
+				}
+				return values, nil
+			}, 10*time.Millisecond, tt.maxBatch, DefaultLoaderTimeout)
+
+			var wg sync.WaitGroup
+			values := make([]string, len(tt.keys))
+			errs := make([]error, len(tt.keys))
+			for i, k := range tt.keys {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					values[i], errs[i] = loader.Load(context.Background(), k)
+				}()
+			}
+			wg.Wait()
+
+			assert.Len(t, batches, tt.expectedBatches)
+			for i, k := range tt.keys {
+				if tt.expectedErr != "" {
+					assert.EqualError(t, errs[i], tt.expectedErr)
+					continue
+				}
+				require.NoError(t, errs[i])
+				assert.Equal(t, fmt.Sprintf("value-%d", k), values[i])
+			}
+		})
+	}
+}
+
+func TestLoader_LoadCancellation(t *testing.T) {
+	t.Run("caller_canceled", func(t *testing.T) {
+		release := make(chan struct{})
+		loader := NewLoader(func(ctx context.Context, keys []int) ([]int, error) {
+			<-release
+			// the batch isn't canceled with the context of the call that dispatched it
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return keys, nil
+		}, time.Millisecond, 0, DefaultLoaderTimeout)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() {
+			_, err := loader.Load(ctx, 1)
+			errs <- err
+		}()
+		values := make(chan int, 1)
+		go func() {
+			v, err := loader.Load(context.Background(), 1)
+			assert.NoError(t, err)
+			values <- v
+		}()
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		require.ErrorIs(t, <-errs, context.Canceled)
+		close(release)
+		assert.Equal(t, 1, <-values)
+	})
+	t.Run("timeout_not_cached", func(t *testing.T) {
+		calls := 0
+		loader := NewLoader(func(ctx context.Context, keys []int) ([]int, error) {
+			calls++
+			if calls == 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return keys, nil
+		}, time.Millisecond, 0, 10*time.Millisecond)
+
+		_, err := loader.Load(context.Background(), 1)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		v, err := loader.Load(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("panic", func(t *testing.T) {
+		loader := NewLoader(func(ctx context.Context, keys []int) ([]int, error) {
+			panic("boom")
+		}, time.Millisecond, 0, DefaultLoaderTimeout)
+
+		var wg sync.WaitGroup
+		for k := range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := loader.Load(context.Background(), k)
+				assert.EqualError(t, err, "batch function panicked: boom")
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestLoad(t *testing.T) {
+	calls := 0
+	fetch := func(ctx context.Context, keys []int) ([]int, error) {
+		calls++
+		values := make([]int, len(keys))
+		for i, k := range keys {
+			values[i] = k * 2
+		}
+		return values, nil
+	}
+
+	t.Run("without_loaders", func(t *testing.T) {
+		calls = 0
+		v, err := Load(context.Background(), "double", 2, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 4, v)
+		v, err = Load(context.Background(), "double", 2, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 4, v)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("with_loaders", func(t *testing.T) {
+		calls = 0
+		ctx := WithLoaders(context.Background())
+		v, err := Load(ctx, "double", 2, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 4, v)
+		// second load is cached by the request scoped loader
+		v, err = Load(ctx, "double", 2, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 4, v)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("type_mismatch", func(t *testing.T) {
+		ctx := WithLoaders(context.Background())
+		_, err := Load(ctx, "double", 2, fetch)
+		require.NoError(t, err)
+		_, err = Load(ctx, "double", "2", func(ctx context.Context, keys []string) ([]int, error) {
+			return nil, nil
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestCompositeKey(t *testing.T) {
+	id := 1
+	assert.Equal(t, CompositeKey(1, "a"), CompositeKey(&id, "a"))
+	assert.NotEqual(t, CompositeKey(1, "a"), CompositeKey(2, "a"))
+	var nilID *int
+	assert.Equal(t, CompositeKey(nil, "a"), CompositeKey(nilID, "a"))
+}
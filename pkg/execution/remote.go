@@ -0,0 +1,341 @@
+package execution
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/spf13/cast"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+	"github.com/roneli/fastgql/pkg/schema"
+)
+
+// FieldExecutor is implemented by executors that can execute an already collected field, MultiExecutor uses it
+// to resolve relations whose type resides in a different dialect than their parent.
+type FieldExecutor interface {
+	// QueryField executes a read query for the given field and scans results into dest
+	QueryField(ctx context.Context, field builders.Field, dest any) error
+}
+
+// resolveRemoteRelations resolves all remote relations of the field (and its nested relations) for the given parent
+// values. Each remote relation is resolved with a single batched query keyed on the relation fields/references,
+// and the results are stitched into the parent objects.
+func (m *MultiExecutor) resolveRemoteRelations(ctx context.Context, field builders.Field, parents []reflect.Value) error {
+	if len(parents) == 0 {
+		return nil
+	}
+	for _, rf := range field.RemoteRelations {
+		if err := m.resolveRemoteRelation(ctx, rf, parents); err != nil {
+			return fmt.Errorf("failed to resolve relation %s: %w", rf.Name, err)
+		}
+	}
+	// walk nested relations resolved by the parent executor, they might hold remote relations of their own
+	for _, sf := range field.Selections {
+		if sf.FieldType != builders.TypeRelation || !hasRemoteRelations(sf) {
+			continue
+		}
+		children := make([]reflect.Value, 0, len(parents))
+		for _, p := range parents {
+			fv, ok := structField(p, sf.Name, "")
+			if !ok {
+				return fmt.Errorf("field %s not found in %s", sf.Name, p.Type())
+			}
+			children = append(children, flatten(fv)...)
+		}
+		if err := m.resolveRemoteRelations(ctx, sf, children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MultiExecutor) resolveRemoteRelation(ctx context.Context, rf builders.Field, parents []reflect.Value) error {
+	rel := rf.Relation()
+	if rel == nil {
+		return builders.InternalErrorf("field %s is missing the @relation directive", rf.Name)
+	}
+	if rel.RelType == schema.ManyToMany {
+		return builders.ValidationErrorf("%s relations across dialects are not supported", rel.RelType)
+	}
+	dialect := m.getDialectForType(rf.TypeDefinition)
+	executor, ok := m.executors[dialect]
+	if !ok {
+		return builders.InternalErrorf("no executor registered for dialect: %s", dialect)
+	}
+	fieldExecutor, ok := executor.(FieldExecutor)
+	if !ok {
+		return fmt.Errorf("executor for dialect %s does not support resolving relations", dialect)
+	}
+
+	// collect distinct parent keys
+	keyFilter := &builders.KeyFilter{Columns: rel.References}
+	parentKeys := make([]*string, len(parents))
+	seen := make(map[string]struct{})
+	for i, p := range parents {
+		values, err := keyValues(p, rel.Fields)
+		if err != nil {
+			return err
+		}
+		if values == nil {
+			continue
+		}
+		k := keyString(values)
+		parentKeys[i] = &k
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		keyFilter.Values = append(keyFilter.Values, values)
+	}
+	if len(keyFilter.Values) == 0 {
+		return nil
+	}
+
+	// find the destination type from the first parent, relation fields are either a slice or a single object
+	destField, ok := structField(parents[0], rf.Name, "")
+	if !ok {
+		return fmt.Errorf("field %s not found in %s", rf.Name, parents[0].Type())
+	}
+	elemType := destField.Type()
+	if elemType.Kind() == reflect.Slice {
+		elemType = elemType.Elem()
+	}
+	results := reflect.New(reflect.SliceOf(elemType))
+	if err := fieldExecutor.QueryField(ctx, batchField(rf, keyFilter), results.Interface()); err != nil {
+		return err
+	}
+
+	// group results by their reference key
+	grouped := make(map[string][]reflect.Value)
+	for i := 0; i < results.Elem().Len(); i++ {
+		child := results.Elem().Index(i)
+		values, err := keyValues(child, rel.References)
+		if err != nil {
+			return err
+		}
+		if values == nil {
+			continue
+		}
+		k := keyString(values)
+		grouped[k] = append(grouped[k], child)
+	}
+	offset, limit := cast.ToInt(rf.Arguments["offset"]), -1
+	if l, ok := rf.Arguments["limit"]; ok {
+		limit = cast.ToInt(l)
+	}
+	for i, p := range parents {
+		if parentKeys[i] == nil {
+			continue
+		}
+		children := paginate(grouped[*parentKeys[i]], offset, limit)
+		fv, _ := structField(p, rf.Name, "")
+		if !fv.CanSet() {
+			return fmt.Errorf("field %s of %s can't be set", rf.Name, p.Type())
+		}
+		if fv.Kind() == reflect.Slice {
+			sliceVal := reflect.MakeSlice(fv.Type(), 0, len(children))
+			fv.Set(reflect.Append(sliceVal, children...))
+		} else if len(children) > 0 {
+			fv.Set(children[0])
+		}
+	}
+	// resolved objects might have remote relations of their own
+	resolved := make([]reflect.Value, 0, results.Elem().Len())
+	for i := 0; i < results.Elem().Len(); i++ {
+		resolved = append(resolved, flatten(results.Elem().Index(i))...)
+	}
+	return m.resolveRemoteRelations(ctx, rf, resolved)
+}
+
+// batchField creates the field used to query all relation rows of the parents at once. Pagination is applied
+// per parent after the results are stitched, and the reference fields are added so results can be matched.
+func batchField(rf builders.Field, keyFilter *builders.KeyFilter) builders.Field {
+	f := rf
+	f.KeyFilter = keyFilter
+	f.Arguments = make(map[string]any, len(rf.Arguments))
+	for k, v := range rf.Arguments {
+		if k == "limit" || k == "offset" {
+			continue
+		}
+		f.Arguments[k] = v
+	}
+	f.Selections = append(builders.Fields{}, rf.Selections...)
+	names := keyFilter.Fields
+	if len(names) == 0 {
+		for _, ref := range keyFilter.Columns {
+			names = append(names, strcase.ToLowerCamel(ref))
+		}
+	}
+	for _, name := range names {
+		if f.Selections.HasSelection(name) {
+			continue
+		}
+		f.Selections = append(f.Selections, builders.Field{
+			Field:     &ast.Field{Name: name, Alias: name, ObjectDefinition: rf.TypeDefinition},
+			FieldType: builders.TypeScalar,
+			Parent:    &f,
+		})
+	}
+	return f
+}
+
+func hasRemoteRelations(f builders.Field) bool {
+	if len(f.RemoteRelations) > 0 {
+		return true
+	}
+	for _, s := range f.Selections {
+		if s.FieldType == builders.TypeRelation && hasRemoteRelations(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyValues returns the values of the key columns in v, nil is returned if any of the key values is nil, including
+// database values that are NULL (i.e. an invalid pgtype.UUID)
+func keyValues(v reflect.Value, columns []string) ([]any, error) {
+	values := make([]any, 0, len(columns))
+	for _, c := range columns {
+		fv, ok := structField(v, strcase.ToLowerCamel(c), c)
+		if !ok {
+			return nil, fmt.Errorf("key field %s not found in %s", c, v.Type())
+		}
+		for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+			if fv.IsNil() {
+				return nil, nil
+			}
+			fv = fv.Elem()
+		}
+		value := fv.Interface()
+		if valuer, ok := value.(driver.Valuer); ok {
+			if dv, err := valuer.Value(); err == nil && dv == nil {
+				return nil, nil
+			}
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// keyString joins the canonical forms of the key values, see keyValueString
+func keyString(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = keyValueString(v)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// keyValueString formats a key value canonically, so keys scanned into different Go types by the executors of the
+// parent and the related type match: database values by their driver value, UUIDs as lower cased hyphenated strings
+// and numbers by their integer value when they have one.
+func keyValueString(v any) string {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if dv, err := valuer.Value(); err == nil && dv != nil {
+			v = dv
+		}
+	}
+	switch v := v.(type) {
+	case [16]byte:
+		return uuidString(v)
+	case string:
+		if isUUIDString(v) {
+			return strings.ToLower(v)
+		}
+		return v
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		// i.e. numbers decoded from JSON
+		if f := rv.Float(); f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+			return strconv.FormatInt(int64(f), 10)
+		}
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// uuidString formats the UUID bytes as a lower cased hyphenated string
+func uuidString(b [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// isUUIDString returns true if s is a hyphenated UUID string
+func isUUIDString(s string) bool {
+	parts := strings.Split(s, "-")
+	if len(s) != 36 || len(parts) != 5 {
+		return false
+	}
+	for i, n := range []int{8, 4, 4, 4, 12} {
+		if _, err := hex.DecodeString(parts[i]); err != nil || len(parts[i]) != n {
+			return false
+		}
+	}
+	return true
+}
+
+// structField finds a field in a struct (or pointer/interface to struct) by its json tag name, db tag name or
+// case-insensitive Go field name
+func structField(v reflect.Value, name, column string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		dbName := strings.Split(sf.Tag.Get("db"), ",")[0]
+		if jsonName == name || (column != "" && dbName == column) || strings.EqualFold(sf.Name, name) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// flatten returns the non-nil objects held by v, v can be a single object or a slice of objects
+func flatten(v reflect.Value) []reflect.Value {
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Slice {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice {
+		values := make([]reflect.Value, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, flatten(v.Index(i))...)
+		}
+		return values
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil
+	}
+	return []reflect.Value{v}
+}
+
+func paginate(values []reflect.Value, offset, limit int) []reflect.Value {
+	if offset >= len(values) {
+		return nil
+	}
+	values = values[offset:]
+	if limit >= 0 && limit < len(values) {
+		values = values[:limit]
+	}
+	return values
+}
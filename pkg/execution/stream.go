@@ -0,0 +1,351 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/roneli/fastgql/pkg/execution/builders"
+)
+
+type (
+	streamsKey       struct{}
+	resumedStreamKey struct{}
+)
+
+// StreamCursor reads the items of a streamed field that weren't delivered in the initial response, it's implemented
+// by the executors that support @stream, i.e. a server-side cursor of the field's query.
+type StreamCursor interface {
+	// Close releases the cursor, it's called once the stream is finished or the request ended
+	Close(ctx context.Context) error
+}
+
+// Stream is a root list field of a query operation with an enabled @stream directive. The executor of the field
+// resolves the first InitialCount items and opens a cursor for the rest with Open, StreamTransport then resolves
+// the field again for each chunk of items until the executor marks the stream as finished.
+type Stream struct {
+	// InitialCount is the number of items delivered in the initial response
+	InitialCount int
+	// Cursor reads the remaining items, it's nil until the executor opens the stream
+	Cursor StreamCursor
+
+	field   *ast.Field
+	label   string
+	streams *operationStreams
+	// finished and closed are guarded by streams.mu
+	finished bool
+	closed   bool
+	// delivered is the number of items delivered, it's only used by StreamTransport once the stream is opened
+	delivered int
+}
+
+// Open sets the cursor reading the remaining items of the stream, which are delivered after the initial response.
+// Streams that aren't opened are delivered in full in the initial response.
+func (s *Stream) Open(cursor StreamCursor) {
+	s.streams.mu.Lock()
+	defer s.streams.mu.Unlock()
+	s.Cursor = cursor
+	// streams that return fewer items than InitialCount are finished
+	s.delivered = s.InitialCount
+	s.streams.opened = append(s.streams.opened, s)
+}
+
+// Finish marks the stream as finished, its cursor has no more items
+func (s *Stream) Finish() {
+	s.streams.mu.Lock()
+	defer s.streams.mu.Unlock()
+	s.finished = true
+}
+
+func (s *Stream) isFinished() bool {
+	s.streams.mu.Lock()
+	defer s.streams.mu.Unlock()
+	return s.finished
+}
+
+// close closes the stream's cursor once
+func (s *Stream) close(ctx context.Context) {
+	s.streams.mu.Lock()
+	defer s.streams.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		_ = s.Cursor.Close(ctx)
+	}
+}
+
+// operationStreams holds the streams opened by an operation
+type operationStreams struct {
+	mu     sync.Mutex
+	opened []*Stream
+}
+
+// pending returns the opened streams that aren't finished, in the order they were opened
+func (o *operationStreams) pending() []*Stream {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var pending []*Stream
+	for _, s := range o.opened {
+		if !s.finished {
+			pending = append(pending, s)
+		}
+	}
+	return pending
+}
+
+// close closes the cursors of the opened streams
+func (o *operationStreams) close(ctx context.Context) {
+	o.mu.Lock()
+	opened := o.opened
+	o.mu.Unlock()
+	for _, s := range opened {
+		s.close(ctx)
+	}
+}
+
+// GetStream returns the stream of the root field in ctx, or nil if the field isn't streamed. Fields are streamed if
+// they're root list fields of a query operation with an enabled @stream directive, sent through StreamTransport.
+// When StreamTransport resolves the next chunk of a stream it returns the opened stream.
+func GetStream(ctx context.Context) (*Stream, error) {
+	if !isRootField(ctx) {
+		return nil, nil
+	}
+	fc := graphql.GetFieldContext(ctx)
+	if s, ok := ctx.Value(resumedStreamKey{}).(*Stream); ok && s.field.Alias == fc.Field.Alias {
+		return s, nil
+	}
+	streams, ok := ctx.Value(streamsKey{}).(*operationStreams)
+	if !ok || fc.Field.Definition == nil || fc.Field.Definition.Type.Elem == nil {
+		return nil, nil
+	}
+	opCtx := graphql.GetOperationContext(ctx)
+	if opCtx.Operation == nil || opCtx.Operation.Operation != ast.Query {
+		return nil, nil
+	}
+	d := fc.Field.Directives.ForName("stream")
+	if d == nil {
+		return nil, nil
+	}
+	args := d.ArgumentMap(opCtx.Variables)
+	if enabled, ok := args["if"].(bool); ok && !enabled {
+		return nil, nil
+	}
+	s := &Stream{streams: streams}
+	if initialCount, ok := args["initialCount"].(int64); ok {
+		if initialCount < 0 {
+			return nil, builders.ValidationErrorf("initialCount of @stream must be non-negative, got %d", initialCount)
+		}
+		s.InitialCount = int(initialCount)
+	}
+	s.label, _ = args["label"].(string)
+	// the chunks are resolved by an operation selecting only the streamed field, with the selections of all the
+	// occurrences of the field merged
+	field := *fc.Field.Field
+	field.SelectionSet = fc.Field.Selections
+	s.field = &field
+	return s, nil
+}
+
+// StreamTransport is a multipart/mixed transport delivering the root list fields of query operations with @stream
+// incrementally, fragments with @defer are delivered as in gqlgen's transport.MultipartMixed. The initial response
+// holds the first initialCount items of each streamed field, the remaining items are read by the field's executor
+// in chunks and each chunk is sent as soon as it's resolved, so a streamed field is never held in memory at once.
+// It must be added to the server before transport.POST, which handles any JSON POST request.
+type StreamTransport struct {
+	// Boundary is the boundary of the multipart response, "-" by default
+	Boundary string
+}
+
+var _ graphql.Transport = StreamTransport{}
+
+// Supports returns true for JSON POST requests accepting multipart/mixed responses
+func (t StreamTransport) Supports(r *http.Request) bool {
+	return transport.MultipartMixed{}.Supports(r)
+}
+
+// Do executes the operation and writes its initial response, deferred fragments and the chunks of its streams
+func (t StreamTransport) Do(w http.ResponseWriter, r *http.Request, exec graphql.GraphExecutor) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		transport.SendErrorf(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	streams := &operationStreams{}
+	ctx := context.WithValue(r.Context(), streamsKey{}, streams)
+	// cursors are released even if the client went away
+	defer streams.close(context.WithoutCancel(ctx))
+
+	w.Header().Set("Content-Type", "application/json")
+	params := &graphql.RawParams{Headers: r.Header}
+	params.ReadTime.Start = graphql.Now()
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(params); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, exec.DispatchError(ctx, gqlerror.List{gqlerror.Errorf("json request body could not be decoded: %+v", err)}))
+		return
+	}
+	params.ReadTime.End = graphql.Now()
+
+	opCtx, errs := exec.CreateOperationContext(ctx, params)
+	if errs != nil {
+		if errcode.GetErrorKind(errs) == errcode.KindProtocol {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+		writeJSON(w, exec.DispatchError(graphql.WithOperationContext(ctx, opCtx), errs))
+		return
+	}
+
+	boundary := t.Boundary
+	if boundary == "" {
+		boundary = "-"
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", fmt.Sprintf(`multipart/mixed;boundary="%s";deferSpec=20220824`, boundary))
+	mw := &multipartWriter{w: w, flusher: flusher, boundary: boundary}
+
+	responses, rctx := exec.DispatchOperation(ctx, opCtx)
+	initial := responses(rctx)
+	pending := streams.pending()
+	hasNext := len(pending) > 0 || initial.HasNext != nil && *initial.HasNext
+	initial.HasNext = &hasNext
+	mw.write(initial)
+	if !hasNext {
+		mw.end()
+		return
+	}
+	for resp := responses(rctx); resp != nil; resp = responses(rctx) {
+		mw.writeIncremental(resp)
+	}
+	for _, s := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		deliver(ctx, exec, opCtx, s, mw)
+	}
+	mw.write(struct {
+		HasNext bool `json:"hasNext"`
+	}{})
+	mw.end()
+}
+
+// deliver resolves the chunks of the stream until it's finished, each chunk is resolved by an operation selecting
+// only the streamed field and sent with the deferred fragments of its items
+func deliver(ctx context.Context, exec graphql.GraphExecutor, opCtx *graphql.OperationContext, s *Stream, mw *multipartWriter) {
+	op := *opCtx.Operation
+	op.SelectionSet = ast.SelectionSet{s.field}
+	ctx = context.WithValue(ctx, resumedStreamKey{}, s)
+	for !s.isFinished() && ctx.Err() == nil {
+		chunkCtx := &graphql.OperationContext{
+			RawQuery:               opCtx.RawQuery,
+			Variables:              opCtx.Variables,
+			OperationName:          opCtx.OperationName,
+			Doc:                    opCtx.Doc,
+			Extensions:             opCtx.Extensions,
+			Headers:                opCtx.Headers,
+			Operation:              &op,
+			DisableIntrospection:   opCtx.DisableIntrospection,
+			RecoverFunc:            opCtx.RecoverFunc,
+			ResolverMiddleware:     opCtx.ResolverMiddleware,
+			RootResolverMiddleware: opCtx.RootResolverMiddleware,
+			Stats:                  opCtx.Stats,
+		}
+		responses, rctx := exec.DispatchOperation(ctx, chunkCtx)
+		resp := responses(rctx)
+		var data map[string][]json.RawMessage
+		if len(resp.Data) > 0 {
+			if err := json.Unmarshal(resp.Data, &data); err != nil {
+				resp.Errors = append(resp.Errors, gqlerror.Errorf("failed to read the items of stream %s: %v", s.field.Alias, err))
+			}
+		}
+		items := data[s.field.Alias]
+		offset := s.delivered
+		if len(items) > 0 || len(resp.Errors) > 0 {
+			mw.write(incrementalPayload{Incremental: []any{streamItems{
+				Items:      items,
+				Errors:     resp.Errors,
+				Path:       ast.Path{ast.PathName(s.field.Alias), ast.PathIndex(offset)},
+				Label:      s.label,
+				Extensions: resp.Extensions,
+			}}, HasNext: true})
+		}
+		s.delivered += len(items)
+		// the paths of deferred fragments are relative to the chunk
+		for resp := responses(rctx); resp != nil; resp = responses(rctx) {
+			if len(resp.Path) > 1 {
+				if i, ok := resp.Path[1].(ast.PathIndex); ok {
+					resp.Path[1] = ast.PathIndex(offset + int(i))
+				}
+			}
+			mw.writeIncremental(resp)
+		}
+		// streams end on their first error, and executors that never finish their streams end on an empty chunk
+		if len(resp.Errors) > 0 || len(items) == 0 {
+			s.Finish()
+		}
+	}
+	// the stream's connection is released before the next stream is delivered
+	s.close(context.WithoutCancel(ctx))
+}
+
+// incrementalPayload is a subsequent payload of an incremental response
+type incrementalPayload struct {
+	Incremental []any `json:"incremental"`
+	HasNext     bool  `json:"hasNext"`
+}
+
+// streamItems is the incremental result of a chunk of a streamed field, Path is the path of the first item
+type streamItems struct {
+	Items      []json.RawMessage `json:"items"`
+	Errors     gqlerror.List     `json:"errors,omitempty"`
+	Path       ast.Path          `json:"path"`
+	Label      string            `json:"label,omitempty"`
+	Extensions map[string]any    `json:"extensions,omitempty"`
+}
+
+// multipartWriter writes the parts of a multipart/mixed response, flushing each part once it's written
+type multipartWriter struct {
+	w        io.Writer
+	flusher  http.Flusher
+	boundary string
+}
+
+// write writes the payload as a part of the response
+func (m *multipartWriter) write(payload any) {
+	_, _ = fmt.Fprintf(m.w, "--%s\r\nContent-Type: application/json\r\n\r\n", m.boundary)
+	b, err := json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Errorf("unable to marshal payload: %w", err))
+	}
+	_, _ = m.w.Write(b)
+	_, _ = fmt.Fprint(m.w, "\r\n")
+	m.flusher.Flush()
+}
+
+// writeIncremental writes a deferred response as a part of the response
+func (m *multipartWriter) writeIncremental(resp *graphql.Response) {
+	resp.HasNext = nil
+	m.write(incrementalPayload{Incremental: []any{resp}, HasNext: true})
+}
+
+// end writes the closing boundary of the response
+func (m *multipartWriter) end() {
+	_, _ = fmt.Fprintf(m.w, "--%s--\r\n", m.boundary)
+	m.flusher.Flush()
+}
+
+func writeJSON(w io.Writer, resp *graphql.Response) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		panic(fmt.Errorf("unable to marshal %s: %w", string(resp.Data), err))
+	}
+	_, _ = w.Write(b)
+}
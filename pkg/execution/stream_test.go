@@ -0,0 +1,182 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const streamSchema = `
+directive @stream(if: Boolean! = true, label: String, initialCount: Int! = 0) on FIELD
+type Query {
+	name: String!
+	numbers: [Int!]!
+}`
+
+// sliceCursor reads the numbers of a stream in chunks of chunkSize
+type sliceCursor struct {
+	numbers   []int
+	chunkSize int
+	closed    bool
+}
+
+func (c *sliceCursor) Close(context.Context) error {
+	c.closed = true
+	return nil
+}
+
+// resolveNumbers resolves the numbers root field the way executors resolve streamed fields
+func resolveNumbers(ctx context.Context, numbers []int, cursors *[]*sliceCursor) (any, error) {
+	s, err := GetStream(ctx)
+	if err != nil || s == nil {
+		return numbers, err
+	}
+	c, ok := s.Cursor.(*sliceCursor)
+	count := 2
+	if !ok {
+		c = &sliceCursor{numbers: numbers, chunkSize: count}
+		*cursors = append(*cursors, c)
+		s.Open(c)
+		count = s.InitialCount
+	}
+	chunk := c.numbers[:min(count, len(c.numbers))]
+	c.numbers = c.numbers[len(chunk):]
+	if len(chunk) < count {
+		s.Finish()
+	}
+	return chunk, nil
+}
+
+// newStreamServer returns a server of streamSchema whose root fields are resolved with their field's context
+func newStreamServer(t *testing.T, numbers []int) (*handler.Server, *[]*sliceCursor) {
+	t.Helper()
+	cursors := &[]*sliceCursor{}
+	es := &graphql.ExecutableSchemaMock{
+		SchemaFunc: func() *ast.Schema {
+			return gqlparser.MustLoadSchema(&ast.Source{Name: "stream.graphql", Input: streamSchema})
+		},
+		ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+			opCtx := graphql.GetOperationContext(ctx)
+			data := make(map[string]any)
+			for _, f := range graphql.CollectFields(opCtx, opCtx.Operation.SelectionSet, []string{"Query"}) {
+				ctx := graphql.WithRootFieldContext(ctx, &graphql.RootFieldContext{Object: "Query", Field: f})
+				ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{Object: "Query", Field: f, IsResolver: true})
+				switch f.Name {
+				case "name":
+					data[f.Alias] = "numbers"
+				case "numbers":
+					v, err := resolveNumbers(ctx, numbers, cursors)
+					if err != nil {
+						graphql.AddError(ctx, err)
+						return graphql.OneShot(&graphql.Response{Data: []byte("null")})
+					}
+					data[f.Alias] = v
+				}
+			}
+			b, err := json.Marshal(data)
+			require.NoError(t, err)
+			return graphql.OneShot(&graphql.Response{Data: b})
+		},
+	}
+	srv := handler.New(es)
+	srv.AddTransport(StreamTransport{})
+	srv.AddTransport(transport.POST{})
+	return srv, cursors
+}
+
+// doStream sends the query to the server accepting a multipart/mixed response and returns its parts
+func doStream(t *testing.T, srv http.Handler, query string) []string {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "multipart/mixed")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType, w.Body.String())
+	var parts []string
+	mr := multipart.NewReader(w.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		require.NoError(t, err)
+		b, err := io.ReadAll(p)
+		require.NoError(t, err)
+		parts = append(parts, string(b))
+	}
+}
+
+func TestStreamTransport(t *testing.T) {
+	t.Run("stream", func(t *testing.T) {
+		srv, cursors := newStreamServer(t, []int{0, 1, 2, 3, 4})
+		parts := doStream(t, srv, `{ name numbers @stream(initialCount: 1, label: "numbers") }`)
+		assert.Equal(t, []string{
+			`{"data":{"name":"numbers","numbers":[0]},"hasNext":true}`,
+			`{"incremental":[{"items":[1,2],"path":["numbers",1],"label":"numbers"}],"hasNext":true}`,
+			`{"incremental":[{"items":[3,4],"path":["numbers",3],"label":"numbers"}],"hasNext":true}`,
+			`{"hasNext":false}`,
+		}, parts)
+		require.Len(t, *cursors, 1)
+		assert.True(t, (*cursors)[0].closed)
+	})
+	t.Run("initial_count_zero", func(t *testing.T) {
+		srv, cursors := newStreamServer(t, []int{0, 1})
+		parts := doStream(t, srv, `{ numbers @stream }`)
+		assert.Equal(t, []string{
+			`{"data":{"numbers":[]},"hasNext":true}`,
+			`{"incremental":[{"items":[0,1],"path":["numbers",0]}],"hasNext":true}`,
+			`{"hasNext":false}`,
+		}, parts)
+		require.Len(t, *cursors, 1)
+		assert.True(t, (*cursors)[0].closed)
+	})
+	t.Run("finished_in_initial_response", func(t *testing.T) {
+		srv, cursors := newStreamServer(t, []int{0, 1})
+		parts := doStream(t, srv, `{ numbers @stream(initialCount: 5) }`)
+		assert.Equal(t, []string{`{"data":{"numbers":[0,1]},"hasNext":false}`}, parts)
+		require.Len(t, *cursors, 1)
+		assert.True(t, (*cursors)[0].closed)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		srv, cursors := newStreamServer(t, []int{0, 1, 2})
+		parts := doStream(t, srv, `{ numbers @stream(if: false) }`)
+		assert.Equal(t, []string{`{"data":{"numbers":[0,1,2]},"hasNext":false}`}, parts)
+		assert.Empty(t, *cursors)
+	})
+	t.Run("negative_initial_count", func(t *testing.T) {
+		srv, cursors := newStreamServer(t, []int{0})
+		parts := doStream(t, srv, `{ numbers @stream(initialCount: -1) }`)
+		require.Len(t, parts, 1)
+		assert.Contains(t, parts[0], "initialCount of @stream must be non-negative")
+		assert.Empty(t, *cursors)
+	})
+	t.Run("without_stream_transport", func(t *testing.T) {
+		srv, cursors := newStreamServer(t, []int{0, 1, 2})
+		r := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query":"{ numbers @stream(initialCount: 1) }"}`))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+		assert.JSONEq(t, `{"data":{"numbers":[0,1,2]}}`, w.Body.String(), "streamed fields are delivered in full")
+		assert.Empty(t, *cursors)
+	})
+}
@@ -0,0 +1,81 @@
+// Package scalars holds the Go types of the custom scalars defined by fastgql
+package scalars
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// GeoJSON holds a PostGIS geometry or geography value as GeoJSON, it is the Go type of the Geometry and Geography scalars.
+// Builders select PostGIS columns using ST_AsGeoJSON, so values are scanned as GeoJSON.
+type GeoJSON json.RawMessage
+
+// UnmarshalGQL implements graphql.Unmarshaler, accepting a GeoJSON object or its string representation
+func (g *GeoJSON) UnmarshalGQL(v any) error {
+	switch v := v.(type) {
+	case string:
+		if !json.Valid([]byte(v)) {
+			return fmt.Errorf("invalid GeoJSON %q", v)
+		}
+		*g = GeoJSON(v)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		*g = data
+	default:
+		return fmt.Errorf("unexpected GeoJSON type %T", v)
+	}
+	return nil
+}
+
+// MarshalGQL implements graphql.Marshaler, writing the GeoJSON object
+func (g GeoJSON) MarshalGQL(w io.Writer) {
+	if len(g) == 0 {
+		_, _ = io.WriteString(w, "null")
+		return
+	}
+	_, _ = w.Write(g)
+}
+
+// MarshalJSON implements json.Marshaler
+func (g GeoJSON) MarshalJSON() ([]byte, error) {
+	if len(g) == 0 {
+		return []byte("null"), nil
+	}
+	return g, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (g *GeoJSON) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*g = nil
+		return nil
+	}
+	*g = append((*g)[:0], data...)
+	return nil
+}
+
+// Scan implements sql.Scanner, scanning the GeoJSON selected by ST_AsGeoJSON
+func (g *GeoJSON) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*g = nil
+	case []byte:
+		*g = append((*g)[:0], src...)
+	case string:
+		*g = GeoJSON(src)
+	case map[string]any:
+		data, err := json.Marshal(src)
+		if err != nil {
+			return err
+		}
+		*g = data
+	default:
+		return fmt.Errorf("unexpected GeoJSON source type %T", src)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package scalars
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoJSON_UnmarshalGQL(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       any
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "object",
+			value:    map[string]any{"type": "Point", "coordinates": []any{1.5, 2}},
+			expected: `{"coordinates":[1.5,2],"type":"Point"}`,
+		},
+		{
+			name:     "string",
+			value:    `{"type":"Point","coordinates":[1,2]}`,
+			expected: `{"type":"Point","coordinates":[1,2]}`,
+		},
+		{
+			name:        "invalid_string",
+			value:       `{"type":`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid_type",
+			value:       1,
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var g GeoJSON
+			err := g.UnmarshalGQL(tt.value)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(g))
+		})
+	}
+}
+
+func TestGeoJSON_Marshal(t *testing.T) {
+	var buf bytes.Buffer
+	GeoJSON(nil).MarshalGQL(&buf)
+	assert.Equal(t, "null", buf.String())
+
+	buf.Reset()
+	point := GeoJSON(`{"type":"Point","coordinates":[1,2]}`)
+	point.MarshalGQL(&buf)
+	assert.Equal(t, string(point), buf.String())
+
+	// GeoJSON nested in a json object, i.e. a relation scanned from jsonb
+	var v struct {
+		Location GeoJSON `json:"location"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"location":{"type":"Point","coordinates":[1,2]}}`), &v))
+	assert.JSONEq(t, string(point), string(v.Location))
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"location":{"type":"Point","coordinates":[1,2]}}`, string(data))
+}
+
+func TestGeoJSON_Scan(t *testing.T) {
+	var g GeoJSON
+	require.NoError(t, g.Scan([]byte(`{"type":"Point","coordinates":[1,2]}`)))
+	assert.JSONEq(t, `{"type":"Point","coordinates":[1,2]}`, string(g))
+	require.NoError(t, g.Scan(map[string]any{"type": "Point", "coordinates": []any{3, 4}}))
+	assert.JSONEq(t, `{"type":"Point","coordinates":[3,4]}`, string(g))
+	require.NoError(t, g.Scan(nil))
+	assert.Nil(t, g)
+	require.Error(t, g.Scan(1))
+}
@@ -0,0 +1,167 @@
+package scalars
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	// DateLayout is the layout of Date values
+	DateLayout = "2006-01-02"
+	// TimeOfDayLayout is the layout of TimeOfDay values, fractional seconds are omitted if zero
+	TimeOfDayLayout = "15:04:05.999999999"
+)
+
+// timeOfDayLayouts are the layouts accepted when parsing a TimeOfDay
+var timeOfDayLayouts = []string{TimeOfDayLayout, "15:04"}
+
+// Date is a calendar date without a time of day, it is the Go type of the Date scalar
+type Date struct {
+	time.Time
+}
+
+// ParseDate parses a date in the 2006-01-02 layout
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+	return Date{t}, nil
+}
+
+// String returns the date in the 2006-01-02 layout
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler
+func (d *Date) UnmarshalGQL(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("unexpected Date type %T", v)
+	}
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalGQL implements graphql.Marshaler
+func (d Date) MarshalGQL(w io.Writer) {
+	_, _ = io.WriteString(w, strconv.Quote(d.String()))
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, dates nested in JSON are selected as strings
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return d.UnmarshalGQL(s)
+}
+
+// Scan implements sql.Scanner
+func (d *Date) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*d = Date{}
+	case time.Time:
+		*d = Date{src}
+	case string:
+		return d.UnmarshalGQL(src)
+	case []byte:
+		return d.UnmarshalGQL(string(src))
+	default:
+		return fmt.Errorf("unexpected Date source type %T", src)
+	}
+	return nil
+}
+
+// TimeOfDay is a time of day without a date, it is the Go type of the TimeOfDay scalar
+type TimeOfDay struct {
+	time.Time
+}
+
+// ParseTimeOfDay parses a time of day i.e. 15:04:05, 15:04:05.123 or 15:04
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	var err error
+	for _, layout := range timeOfDayLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return TimeOfDay{t}, nil
+		}
+	}
+	return TimeOfDay{}, fmt.Errorf("invalid time %q: %w", s, err)
+}
+
+// String returns the time of day in the 15:04:05 layout
+func (t TimeOfDay) String() string {
+	return t.Format(TimeOfDayLayout)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler
+func (t *TimeOfDay) UnmarshalGQL(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("unexpected TimeOfDay type %T", v)
+	}
+	parsed, err := ParseTimeOfDay(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalGQL implements graphql.Marshaler
+func (t TimeOfDay) MarshalGQL(w io.Writer) {
+	_, _ = io.WriteString(w, strconv.Quote(t.String()))
+}
+
+// MarshalJSON implements json.Marshaler
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, times nested in JSON are selected as strings
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return t.UnmarshalGQL(s)
+}
+
+// Scan implements sql.Scanner
+func (t *TimeOfDay) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*t = TimeOfDay{}
+	case time.Time:
+		*t = TimeOfDay{time.Date(0, time.January, 1, src.Hour(), src.Minute(), src.Second(), src.Nanosecond(), time.UTC)}
+	case string:
+		return t.UnmarshalGQL(src)
+	case []byte:
+		return t.UnmarshalGQL(string(src))
+	default:
+		return fmt.Errorf("unexpected TimeOfDay source type %T", src)
+	}
+	return nil
+}
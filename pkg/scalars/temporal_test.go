@@ -0,0 +1,106 @@
+package scalars
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate_UnmarshalGQL(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       any
+		expected    string
+		expectedErr bool
+	}{
+		{name: "date", value: "2024-02-29", expected: "2024-02-29"},
+		{name: "timestamp", value: "2024-02-29T10:00:00Z", expectedErr: true},
+		{name: "invalid_date", value: "2023-02-29", expectedErr: true},
+		{name: "invalid_type", value: 20240229, expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Date
+			err := d.UnmarshalGQL(tt.value)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d.String())
+		})
+	}
+}
+
+func TestDate_Marshal(t *testing.T) {
+	d, err := ParseDate("2024-01-15")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	d.MarshalGQL(&buf)
+	assert.Equal(t, `"2024-01-15"`, buf.String())
+
+	// dates nested in a json object, i.e. a relation scanned from jsonb
+	var v struct {
+		Day Date `json:"day"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"day":"2024-01-15"}`), &v))
+	assert.Equal(t, d, v.Day)
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"day":"2024-01-15"}`, string(data))
+}
+
+func TestDate_Scan(t *testing.T) {
+	var d Date
+	require.NoError(t, d.Scan(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2024-01-15", d.String())
+	require.NoError(t, d.Scan([]byte("2024-03-01")))
+	assert.Equal(t, "2024-03-01", d.String())
+	require.NoError(t, d.Scan(nil))
+	assert.True(t, d.IsZero())
+	require.Error(t, d.Scan(1))
+}
+
+func TestTimeOfDay_UnmarshalGQL(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       any
+		expected    string
+		expectedErr bool
+	}{
+		{name: "seconds", value: "09:30:15", expected: "09:30:15"},
+		{name: "fraction", value: "09:30:15.250", expected: "09:30:15.25"},
+		{name: "minutes", value: "09:30", expected: "09:30:00"},
+		{name: "invalid_time", value: "25:00", expectedErr: true},
+		{name: "invalid_type", value: 930, expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tod TimeOfDay
+			err := tod.UnmarshalGQL(tt.value)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tod.String())
+		})
+	}
+}
+
+func TestTimeOfDay_Scan(t *testing.T) {
+	var tod TimeOfDay
+	require.NoError(t, tod.Scan(time.Date(2024, time.January, 15, 18, 5, 0, 0, time.UTC)))
+	assert.Equal(t, "18:05:00", tod.String())
+	require.NoError(t, tod.Scan("07:00:01"))
+	assert.Equal(t, "07:00:01", tod.String())
+
+	var buf bytes.Buffer
+	tod.MarshalGQL(&buf)
+	assert.Equal(t, `"07:00:01"`, buf.String())
+	require.Error(t, tod.Scan(1.5))
+}
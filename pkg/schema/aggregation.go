@@ -3,6 +3,8 @@ package schema
 import (
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 
 	"github.com/iancoleman/strcase"
 
@@ -11,33 +13,58 @@ import (
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
-type aggregate struct {
-	name               string
-	allowedScalarTypes []string
-	kind               string
+// Aggregate is an aggregate function added to the generated aggregate payloads, i.e. max or countDistinct
+type Aggregate struct {
+	// Name of the aggregate payload field, the SQL builder aggregates it with the builders.AggregateFunc of the same name
+	Name string
+	// AllowedScalarTypes are the types of the fields that can be aggregated, if empty fields of all scalar types are allowed
+	AllowedScalarTypes []string
+	// Kind is the result type of the aggregate, if empty the aggregated field's type is used
+	Kind string
+	// List marks the result as a list of the result type, i.e. arrayAgg
+	List bool
+	// Arguments of the aggregate payload field, i.e. percentile(p: Float!)
+	Arguments ast.ArgumentDefinitionList
 }
 
-var aggregateTypes = []aggregate{
+// defaultAggregates are the aggregates of the generated aggregate payloads, custom aggregates are added to them
+// with NewAggregationAugmenter or the WithAggregates plugin option
+var defaultAggregates = []Aggregate{
 	{
-		name:               "max",
-		allowedScalarTypes: []string{"Int", "Float", "String", "DateTime", "ID"},
+		Name:               "max",
+		AllowedScalarTypes: []string{"Int", "Float", "String", "Date", "DateTime", "TimeOfDay", "ID"},
 	},
 	{
-		name:               "min",
-		allowedScalarTypes: []string{"Int", "Float", "String", "DateTime", "ID"},
+		Name:               "min",
+		AllowedScalarTypes: []string{"Int", "Float", "String", "Date", "DateTime", "TimeOfDay", "ID"},
 	},
 	{
-		name:               "avg",
-		allowedScalarTypes: []string{"Int", "Float"},
-		kind:               "Float",
+		Name:               "avg",
+		AllowedScalarTypes: []string{"Int", "Float"},
+		Kind:               "Float",
 	},
 	{
-		name:               "sum",
-		allowedScalarTypes: []string{"Int", "Float"},
-		kind:               "Float",
+		Name:               "sum",
+		AllowedScalarTypes: []string{"Int", "Float"},
+		Kind:               "Float",
 	},
 }
 
+// withAggregates returns the default aggregates along with the custom aggregates, custom aggregates replace default
+// aggregates of the same name
+func withAggregates(custom []Aggregate) []Aggregate {
+	aggregates := slices.Clone(defaultAggregates)
+	for _, a := range custom {
+		i := slices.IndexFunc(aggregates, func(at Aggregate) bool { return at.Name == a.Name })
+		if i < 0 {
+			aggregates = append(aggregates, a)
+			continue
+		}
+		aggregates[i] = a
+	}
+	return aggregates
+}
+
 type Aggregation struct{}
 
 func (a Aggregation) DirectiveName() string {
@@ -48,7 +75,22 @@ func (a Aggregation) Name() string {
 	return "aggregation"
 }
 
+// AggregationAugmenter adds the aggregate fields of the default aggregates to @generate list fields
 func AggregationAugmenter(s *ast.Schema) error {
+	return augmentAggregation(s, defaultAggregates)
+}
+
+// NewAggregationAugmenter returns an AggregationAugmenter adding the custom aggregates to the generated aggregate
+// payloads, replacing default aggregates of the same name. Each custom aggregate must have a matching
+// builders.AggregateFunc in the builders.Config CustomAggregates.
+func NewAggregationAugmenter(custom ...Aggregate) Augmenter {
+	aggregates := withAggregates(custom)
+	return func(s *ast.Schema) error {
+		return augmentAggregation(s, aggregates)
+	}
+}
+
+func augmentAggregation(s *ast.Schema, aggregates []Aggregate) error {
 	for _, v := range s.Query.Fields {
 		d := v.Directives.ForName(generateDirectiveName)
 		if d == nil {
@@ -60,19 +102,20 @@ func AggregationAugmenter(s *ast.Schema) error {
 		log.Printf("adding aggregation field to query %s@%s\n", v.Name, s.Query.Name)
 		args := d.ArgumentMap(nil)
 		if p, ok := args["aggregate"]; ok && cast.ToBool(p) {
-			addAggregationField(s, s.Query, v)
+			addAggregationField(s, s.Query, v, aggregates)
 		}
 	}
 	return nil
 }
 
-func addAggregationField(s *ast.Schema, obj *ast.Definition, field *ast.FieldDefinition) {
+func addAggregationField(s *ast.Schema, obj *ast.Definition, field *ast.FieldDefinition, aggregates []Aggregate) {
 	t := GetType(field.Type)
 	fieldDef, ok := s.Types[t.Name()]
-	if !ok || !fieldDef.IsCompositeType() {
+	// unions have no fields of their own to aggregate on
+	if !ok || !fieldDef.IsCompositeType() || fieldDef.Kind == ast.Union {
 		return
 	}
-	aggDef := addAggregateObject(s, fieldDef)
+	aggDef := addAggregateObject(s, fieldDef, aggregates)
 	if aggDef == nil {
 		log.Printf("aggreationField for field %s@%s already exists skipping\n", field.Name, obj.Name)
 		return
@@ -96,20 +139,49 @@ func addAggregationField(s *ast.Schema, obj *ast.Definition, field *ast.FieldDef
 
 func addAggregateField(s *ast.Schema, obj *ast.Definition, field *ast.FieldDefinition, aggDef *ast.Definition) {
 	aggregateName := fmt.Sprintf("_%sAggregate", field.Name)
+	typeName := strcase.ToCamel(field.Type.Name())
+	having := addAggregateHavingInput(s, typeName, aggDef)
+	ordering := addAggregateOrderingInput(s, typeName, aggDef)
+	var functionArgs ast.ArgumentDefinitionList
+	// aggregates of @function fields call the function with the same arguments
+	if GetFunctionDirective(field) != nil {
+		functionArgs = FunctionArguments(field)
+	}
 	obj.Fields = append(obj.Fields, &ast.FieldDefinition{
 		Name:        aggregateName,
 		Description: fmt.Sprintf("%s Aggregate", field.Name),
-		Arguments: ast.ArgumentDefinitionList{
+		Arguments: append(functionArgs, ast.ArgumentDefinitionList{
 			{
 				Name: "groupBy",
 				Type: &ast.Type{
 					Elem: &ast.Type{
-						NamedType: fmt.Sprintf("%sGroupBy", strcase.ToCamel(field.Type.Name())),
+						NamedType: fmt.Sprintf("%sGroupBy", typeName),
 						NonNull:   true,
 					},
 				},
 			},
-		},
+			{
+				Name: "having",
+				Type: &ast.Type{NamedType: having.Name},
+			},
+			{
+				Name: "orderBy",
+				Type: &ast.Type{
+					Elem: &ast.Type{
+						NamedType: ordering.Name,
+						NonNull:   true,
+					},
+				},
+			},
+			{
+				Name: "limit",
+				Type: &ast.Type{NamedType: "Int"},
+			},
+			{
+				Name: "offset",
+				Type: &ast.Type{NamedType: "Int"},
+			},
+		}...),
 		Type: &ast.Type{
 			Elem: &ast.Type{
 				NamedType: aggDef.Name,
@@ -122,6 +194,73 @@ func addAggregateField(s *ast.Schema, obj *ast.Definition, field *ast.FieldDefin
 	})
 }
 
+// addAggregateHavingInput adds the having input of the aggregate, filtering groups by the count and the aggregated
+// fields using the comparators of their types, i.e. {count: {gt: 1}, max: {price: {lt: 10}}}
+func addAggregateHavingInput(s *ast.Schema, typeName string, aggDef *ast.Definition) *ast.Definition {
+	return addAggregateInput(s, typeName, "Having", aggDef, func(t string) string {
+		if _, ok := s.Types[t+"Comparator"]; !ok {
+			return ""
+		}
+		return t + "Comparator"
+	})
+}
+
+// addAggregateOrderingInput adds the ordering input of the aggregate, ordering groups by the count and the aggregated
+// fields, i.e. {count: DESC} or {sum: {price: ASC}}
+func addAggregateOrderingInput(s *ast.Schema, typeName string, aggDef *ast.Definition) *ast.Definition {
+	return addAggregateInput(s, typeName, "Ordering", aggDef, func(string) string {
+		return "_OrderingTypes"
+	})
+}
+
+// addAggregateInput adds an input named _<Type>Aggregate<suffix>, with a count field and a nested _<Type><Aggregate><suffix>
+// input for every aggregate of the payload object. fieldType returns the input type of a field of the given type,
+// fields are skipped if it's empty.
+func addAggregateInput(s *ast.Schema, typeName, suffix string, aggDef *ast.Definition, fieldType func(string) string) *ast.Definition {
+	inputName := fmt.Sprintf("_%sAggregate%s", typeName, suffix)
+	if def, ok := s.Types[inputName]; ok {
+		return def
+	}
+	input := &ast.Definition{
+		Kind:        ast.InputObject,
+		Name:        inputName,
+		Description: fmt.Sprintf("%s of %s aggregates", suffix, typeName),
+	}
+	for _, af := range aggDef.Fields {
+		// aggregates taking arguments can't be compared without them, i.e. percentile(p: Float!)
+		if af.Name == "group" || len(af.Arguments) > 0 {
+			continue
+		}
+		aggObj := s.Types[af.Type.Name()]
+		if aggObj == nil || !aggObj.IsCompositeType() {
+			if t := fieldType(af.Type.Name()); t != "" {
+				input.Fields = append(input.Fields, &ast.FieldDefinition{Name: af.Name, Type: &ast.Type{NamedType: t}})
+			}
+			continue
+		}
+		nested := &ast.Definition{
+			Kind:        ast.InputObject,
+			Name:        fmt.Sprintf("_%s%s%s", typeName, strcase.ToCamel(af.Name), suffix),
+			Description: fmt.Sprintf("%s of %s %s aggregates", suffix, typeName, af.Name),
+		}
+		for _, f := range aggObj.Fields {
+			if IsListType(f.Type) {
+				continue
+			}
+			if t := fieldType(f.Type.Name()); t != "" {
+				nested.Fields = append(nested.Fields, &ast.FieldDefinition{Name: f.Name, Type: &ast.Type{NamedType: t}})
+			}
+		}
+		if len(nested.Fields) == 0 {
+			continue
+		}
+		s.Types[nested.Name] = nested
+		input.Fields = append(input.Fields, &ast.FieldDefinition{Name: af.Name, Type: &ast.Type{NamedType: nested.Name}})
+	}
+	s.Types[inputName] = input
+	return input
+}
+
 func addGenerateDirective(s *ast.Schema) *ast.Directive {
 	return &ast.Directive{
 		Name: generateDirectiveName,
@@ -139,7 +278,7 @@ func addGenerateDirective(s *ast.Schema) *ast.Directive {
 }
 
 // addAggregateGroupByObject builds the group by object for the aggregate
-func addAggregateGroupByObject(s *ast.Schema, obj *ast.Definition) {
+func addAggregateGroupByObject(s *ast.Schema, obj *ast.Definition, aggregates []Aggregate) {
 	// check if group by object already exists, if so, skip
 	if _, ok := s.Types[fmt.Sprintf("%sGroupBy", obj.Name)]; ok {
 		log.Printf("group by object for %s already exists skipping\n", obj.Name)
@@ -166,30 +305,50 @@ func addAggregateGroupByObject(s *ast.Schema, obj *ast.Definition) {
 			Description: fmt.Sprintf("Group by %s", f.Name),
 			Name:        strcase.ToScreamingSnake(f.Name),
 		})
+		for _, b := range timeBucketsOf(t.Name()) {
+			groupBy.EnumValues = append(groupBy.EnumValues, &ast.EnumValueDefinition{
+				Description: fmt.Sprintf("Group by %s truncated to the %s", f.Name, b),
+				Name:        timeBucketGroupByName(f.Name, b),
+			})
+		}
+	}
+	for _, r := range relationGroupBys(s, obj) {
+		log.Printf("adding relation field %s.%s to group by aggregates for %s\n", r.Relation.Name, r.Field.Name, obj.Name)
+		groupBy.EnumValues = append(groupBy.EnumValues, &ast.EnumValueDefinition{
+			Description: fmt.Sprintf("Group by %s of %s", r.Field.Name, r.Relation.Name),
+			Name:        r.Name(),
+		})
+	}
+	for _, j := range jsonGroupBys(s, obj) {
+		log.Printf("adding json field %s.%s to group by aggregates for %s\n", j.JSON.Name, strings.Join(j.Keys(), "."), obj.Name)
+		groupBy.EnumValues = append(groupBy.EnumValues, &ast.EnumValueDefinition{
+			Description: fmt.Sprintf("Group by %s of %s", strings.Join(j.Keys(), "."), j.JSON.Name),
+			Name:        j.Name(),
+		})
 	}
 	// add object to schema
 	s.Types[groupBy.Name] = groupBy
-	addRecursiveAggregation(s, obj)
+	addRecursiveAggregation(s, obj, aggregates)
 }
 
-func addRecursiveAggregation(s *ast.Schema, obj *ast.Definition) {
+func addRecursiveAggregation(s *ast.Schema, obj *ast.Definition, aggregates []Aggregate) {
 	for _, f := range obj.Fields {
 		// aggregate only on fields with the @relation directive
 		if f.Directives.ForName("relation") == nil {
 			continue
 		}
 		def := s.Types[f.Type.Name()]
-		aggDef := addAggregateObject(s, def)
+		aggDef := addAggregateObject(s, def, aggregates)
 		if def != nil {
 			addAggregateField(s, obj, f, aggDef)
 		}
 	}
 }
 
-func addAggregateObject(s *ast.Schema, obj *ast.Definition) *ast.Definition {
+func addAggregateObject(s *ast.Schema, obj *ast.Definition, aggregates []Aggregate) *ast.Definition {
 	payloadObjectName := fmt.Sprintf("%sAggregate", inflection.Plural(obj.Name))
 	// Add group by if not exists
-	addAggregateGroupByObject(s, obj)
+	addAggregateGroupByObject(s, obj, aggregates)
 	if payloadObject, ok := s.Types[payloadObjectName]; ok {
 		return payloadObject
 	}
@@ -217,7 +376,7 @@ func addAggregateObject(s *ast.Schema, obj *ast.Definition) *ast.Definition {
 		},
 	}
 	// Add other aggregate functions
-	for _, a := range aggregateTypes {
+	for _, a := range aggregates {
 		af := addAggregationFieldToSchema(s, obj, a)
 		if af == nil {
 			continue
@@ -228,8 +387,8 @@ func addAggregateObject(s *ast.Schema, obj *ast.Definition) *ast.Definition {
 	return payloadObject
 }
 
-func addAggregationFieldToSchema(s *ast.Schema, obj *ast.Definition, a aggregate) *ast.FieldDefinition {
-	aggregateName := fmt.Sprintf("_%s%s", obj.Name, strcase.ToCamel(a.name))
+func addAggregationFieldToSchema(s *ast.Schema, obj *ast.Definition, a Aggregate) *ast.FieldDefinition {
+	aggregateName := fmt.Sprintf("_%s%s", obj.Name, strcase.ToCamel(a.Name))
 	// check if field already exists, if so, skip
 	if def := obj.Fields.ForName(aggregateName); def != nil {
 		log.Printf("aggreationField for field %s@%s already exists skipping\n", aggregateName, obj.Name)
@@ -243,10 +402,11 @@ func addAggregationFieldToSchema(s *ast.Schema, obj *ast.Definition, a aggregate
 	aggObj := &ast.Definition{
 		Kind:        ast.Object,
 		Name:        aggregateName,
-		Description: fmt.Sprintf("%s Aggregate", a.name),
+		Description: fmt.Sprintf("%s Aggregate", a.Name),
 	}
 	for _, f := range obj.Fields {
-		if IsListType(f.Type) {
+		// computed fields can be grouped by, but aren't aggregated
+		if IsListType(f.Type) || GetComputedDirective(f) != nil {
 			continue
 		}
 		t := GetType(f.Type)
@@ -255,21 +415,22 @@ func addAggregationFieldToSchema(s *ast.Schema, obj *ast.Definition, a aggregate
 		if !fieldDef.IsLeafType() {
 			continue
 		}
-		if !scalarAllowed(t.Name(), a.allowedScalarTypes) {
+		if !aggregateAllowed(s, a, t.Name()) {
 			continue
 		}
-		kind := a.kind
-		if kind == "" {
-			kind = t.Name()
+		kind := aggregateKind(s, a, t.Name())
+		log.Printf("adding field %s[%s] to aggregates[type:%s] for %s\n", f.Name, kind, a.Name, obj.Name)
+		fieldType := &ast.Type{
+			NamedType: kind,
+			NonNull:   true,
+		}
+		if a.List {
+			fieldType = &ast.Type{Elem: &ast.Type{NamedType: kind}, NonNull: true}
 		}
-		log.Printf("adding field %s[%s] to aggregates[type:%s] for %s\n", f.Name, kind, a.name, obj.Name)
 		aggObj.Fields = append(aggObj.Fields, &ast.FieldDefinition{
-			Description: fmt.Sprintf("Compute the %s for %s", a.name, f.Name),
+			Description: fmt.Sprintf("Compute the %s for %s", a.Name, f.Name),
 			Name:        f.Name,
-			Type: &ast.Type{
-				NamedType: kind,
-				NonNull:   true,
-			},
+			Type:        fieldType,
 		})
 	}
 	// if no fields are added, skip
@@ -279,8 +440,9 @@ func addAggregationFieldToSchema(s *ast.Schema, obj *ast.Definition, a aggregate
 	// add object to schema
 	s.Types[aggregateName] = aggObj
 	return &ast.FieldDefinition{
-		Name:        a.name,
-		Description: fmt.Sprintf("%s Aggregate", strcase.ToCamel(a.name)),
+		Name:        a.Name,
+		Description: fmt.Sprintf("%s Aggregate", strcase.ToCamel(a.Name)),
+		Arguments:   a.Arguments,
 		Type: &ast.Type{
 			NamedType: aggregateName,
 			NonNull:   true,
@@ -288,6 +450,31 @@ func addAggregationFieldToSchema(s *ast.Schema, obj *ast.Definition, a aggregate
 	}
 }
 
+// aggregateAllowed returns true if fields of the scalar can be aggregated by the aggregate, declared scalars can be
+// aggregated by max and min if they're orderable, and by sum and avg if they're aggregatable
+func aggregateAllowed(s *ast.Schema, a Aggregate, scalar string) bool {
+	if sc, ok := GetScalar(s, scalar); ok {
+		switch a.Name {
+		case "max", "min":
+			return sc.Orderable
+		case "sum", "avg":
+			return sc.Aggregatable
+		}
+	}
+	return len(a.AllowedScalarTypes) == 0 || scalarAllowed(scalar, a.AllowedScalarTypes)
+}
+
+// aggregateKind returns the result type of the aggregate of a field of the scalar
+func aggregateKind(s *ast.Schema, a Aggregate, scalar string) string {
+	if sc, ok := GetScalar(s, scalar); ok && sc.AggregateType != "" && (a.Name == "sum" || a.Name == "avg") {
+		return sc.AggregateType
+	}
+	if a.Kind != "" {
+		return a.Kind
+	}
+	return scalar
+}
+
 func scalarAllowed(scalar string, allowed []string) bool {
 	for _, v := range allowed {
 		if scalar == v {
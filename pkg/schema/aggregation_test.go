@@ -90,6 +90,24 @@ func Test_AggregationAugmenter_Unit(t *testing.T) {
 			queryField:           "users",
 			expectAggregateField: false,
 		},
+		{
+			name: "skips_union_fields",
+			schemaDefinition: `
+				type Post {
+					id: ID!
+				}
+				type Comment {
+					id: ID!
+				}
+				union FeedItem = Post | Comment
+				type Query {
+					feed: [FeedItem] @generate(aggregate: true)
+				}
+			`,
+			queryField:           "feed",
+			expectAggregateField: false,
+			aggregateFieldName:   "_feedAggregate",
+		},
 	}
 
 	for _, tt := range tests {
@@ -176,7 +194,7 @@ func Test_addAggregateObject(t *testing.T) {
 			objDef := schema.Types[tt.objectType]
 			require.NotNil(t, objDef)
 
-			result := addAggregateObject(schema, objDef)
+			result := addAggregateObject(schema, objDef, defaultAggregates)
 			require.NotNil(t, result, "Aggregate object should be created")
 			assert.Equal(t, tt.expectedAggregate, result.Name)
 
@@ -211,17 +229,17 @@ func Test_addAggregateObject(t *testing.T) {
 func Test_addAggregationFieldToSchema(t *testing.T) {
 	tests := []struct {
 		name           string
-		aggregateType  aggregate
+		aggregateType  Aggregate
 		objectFields   []fieldInfo
 		expectFields   []string
 		unexpectedFields []string
 	}{
 		{
 			name: "sum_includes_only_numeric_types",
-			aggregateType: aggregate{
-				name:               "sum",
-				allowedScalarTypes: []string{"Int", "Float"},
-				kind:               "Float",
+			aggregateType: Aggregate{
+				Name:               "sum",
+				AllowedScalarTypes: []string{"Int", "Float"},
+				Kind:               "Float",
 			},
 			objectFields: []fieldInfo{
 				{name: "id", typeName: "ID", isList: false},
@@ -234,10 +252,10 @@ func Test_addAggregationFieldToSchema(t *testing.T) {
 		},
 		{
 			name: "avg_includes_only_numeric_types",
-			aggregateType: aggregate{
-				name:               "avg",
-				allowedScalarTypes: []string{"Int", "Float"},
-				kind:               "Float",
+			aggregateType: Aggregate{
+				Name:               "avg",
+				AllowedScalarTypes: []string{"Int", "Float"},
+				Kind:               "Float",
 			},
 			objectFields: []fieldInfo{
 				{name: "age", typeName: "Int", isList: false},
@@ -248,9 +266,9 @@ func Test_addAggregationFieldToSchema(t *testing.T) {
 		},
 		{
 			name: "min_includes_multiple_types",
-			aggregateType: aggregate{
-				name:               "min",
-				allowedScalarTypes: []string{"Int", "Float", "String", "DateTime", "ID"},
+			aggregateType: Aggregate{
+				Name:               "min",
+				AllowedScalarTypes: []string{"Int", "Float", "String", "DateTime", "ID"},
 			},
 			objectFields: []fieldInfo{
 				{name: "id", typeName: "ID", isList: false},
@@ -263,10 +281,10 @@ func Test_addAggregationFieldToSchema(t *testing.T) {
 		},
 		{
 			name: "skips_list_fields",
-			aggregateType: aggregate{
-				name:               "sum",
-				allowedScalarTypes: []string{"Int", "Float"},
-				kind:               "Float",
+			aggregateType: Aggregate{
+				Name:               "sum",
+				AllowedScalarTypes: []string{"Int", "Float"},
+				Kind:               "Float",
 			},
 			objectFields: []fieldInfo{
 				{name: "age", typeName: "Int", isList: false},
@@ -275,6 +293,19 @@ func Test_addAggregationFieldToSchema(t *testing.T) {
 			expectFields:     []string{"age"},
 			unexpectedFields: []string{"scores"},
 		},
+		{
+			name: "custom_without_allowed_types_includes_all_scalars",
+			aggregateType: Aggregate{
+				Name: "countDistinct",
+				Kind: "Int",
+			},
+			objectFields: []fieldInfo{
+				{name: "age", typeName: "Int", isList: false},
+				{name: "name", typeName: "String", isList: false},
+				{name: "active", typeName: "Boolean", isList: false},
+			},
+			expectFields: []string{"age", "name", "active"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -293,7 +324,7 @@ func Test_addAggregationFieldToSchema(t *testing.T) {
 			require.NotNil(t, result, "Should return aggregate field definition")
 
 			// Check the aggregate type was created in schema
-			aggTypeName := fmt.Sprintf("_TestObject%s", strcase.ToCamel(tt.aggregateType.name))
+			aggTypeName := fmt.Sprintf("_TestObject%s", strcase.ToCamel(tt.aggregateType.Name))
 			aggType, exists := schema.Types[aggTypeName]
 			require.True(t, exists, "Aggregate type should be created in schema")
 
@@ -312,6 +343,61 @@ func Test_addAggregationFieldToSchema(t *testing.T) {
 	}
 }
 
+// Test_addAggregationFieldToSchema_Custom tests the result type and arguments of custom aggregates
+func Test_addAggregationFieldToSchema_Custom(t *testing.T) {
+	s := buildSchemaWithFields(t, "TestObject", []fieldInfo{
+		{name: "age", typeName: "Int"},
+		{name: "name", typeName: "String"},
+	})
+	objDef := s.Types["TestObject"]
+
+	arrayAgg := addAggregationFieldToSchema(s, objDef, Aggregate{Name: "arrayAgg", List: true})
+	require.NotNil(t, arrayAgg)
+	assert.Equal(t, "[String]!", s.Types["_TestObjectArrayAgg"].Fields.ForName("name").Type.String())
+
+	percentile := addAggregationFieldToSchema(s, objDef, Aggregate{
+		Name:               "percentile",
+		AllowedScalarTypes: []string{"Int", "Float"},
+		Kind:               "Float",
+		Arguments:          ast.ArgumentDefinitionList{{Name: "p", Type: ast.NonNullNamedType("Float", nil)}},
+	})
+	require.NotNil(t, percentile)
+	require.NotNil(t, percentile.Arguments.ForName("p"))
+	assert.Equal(t, "Float!", s.Types["_TestObjectPercentile"].Fields.ForName("age").Type.String())
+	assert.Nil(t, s.Types["_TestObjectPercentile"].Fields.ForName("name"))
+}
+
+func Test_withAggregates(t *testing.T) {
+	aggregates := withAggregates([]Aggregate{
+		{Name: "countDistinct", Kind: "Int"},
+		{Name: "max", AllowedScalarTypes: []string{"Int"}},
+	})
+	require.Len(t, aggregates, len(defaultAggregates)+1)
+	assert.Equal(t, "countDistinct", aggregates[len(aggregates)-1].Name)
+	assert.Equal(t, []string{"Int"}, aggregates[0].AllowedScalarTypes)
+	assert.NotEqual(t, []string{"Int"}, defaultAggregates[0].AllowedScalarTypes, "default aggregates aren't modified")
+}
+
+func TestNewAggregationAugmenter(t *testing.T) {
+	schemaDefinition := `
+		type Post @generateFilterInput {
+			id: Int!
+			name: String
+		}
+		type Query {
+			posts: [Post] @generate
+		}
+	`
+	s := buildTestSchema(t, schemaDefinition)
+	require.NoError(t, NewAggregationAugmenter(Aggregate{Name: "countDistinct", Kind: "Int"})(s))
+	require.NotNil(t, s.Types["PostsAggregate"].Fields.ForName("countDistinct"))
+	assert.Equal(t, "Int!", s.Types["_PostCountDistinct"].Fields.ForName("name").Type.String())
+
+	s = buildTestSchema(t, schemaDefinition)
+	require.NoError(t, AggregationAugmenter(s))
+	assert.Nil(t, s.Types["PostsAggregate"].Fields.ForName("countDistinct"), "custom aggregates are scoped to their augmenter")
+}
+
 // Test_scalarAllowed tests the scalar type checking
 func Test_scalarAllowed(t *testing.T) {
 	tests := []struct {
@@ -392,6 +478,43 @@ func Test_addAggregateGroupByObject(t *testing.T) {
 			expectedValues: []string{"ID"},
 			skipValues:     []string{"PROFILE"},
 		},
+		{
+			name: "adds_time_buckets_for_temporal_fields",
+			schemaDefinition: `
+				type User {
+					id: ID!
+					createdAt: DateTime!
+					birthday: Date
+					wakeUp: TimeOfDay
+				}
+			`,
+			objectType:     "User",
+			expectedEnum:   "UserGroupBy",
+			expectedValues: []string{"CREATED_AT", "CREATED_AT_HOUR", "CREATED_AT_DAY", "CREATED_AT_WEEK", "CREATED_AT_MONTH", "BIRTHDAY", "BIRTHDAY_DAY", "BIRTHDAY_WEEK", "BIRTHDAY_MONTH", "WAKE_UP"},
+			skipValues:     []string{"BIRTHDAY_HOUR", "WAKE_UP_HOUR", "ID_DAY"},
+		},
+		{
+			name: "adds_one_to_one_relation_fields",
+			schemaDefinition: `
+				type Post {
+					id: ID!
+					user: User @relation(type: ONE_TO_ONE, fields: ["user_id"], references: ["id"])
+					tags: [Tag] @relation(type: ONE_TO_MANY, fields: ["id"], references: ["post_id"])
+				}
+				type User {
+					id: ID!
+					name: String
+					posts: [Post]
+				}
+				type Tag {
+					name: String
+				}
+			`,
+			objectType:     "Post",
+			expectedEnum:   "PostGroupBy",
+			expectedValues: []string{"ID", "USER__ID", "USER__NAME"},
+			skipValues:     []string{"USER", "USER__POSTS", "TAGS__NAME"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -401,7 +524,7 @@ func Test_addAggregateGroupByObject(t *testing.T) {
 			require.NotNil(t, objDef)
 
 			// Call the function
-			addAggregateGroupByObject(schema, objDef)
+			addAggregateGroupByObject(schema, objDef, defaultAggregates)
 
 			// Check enum was created
 			enumDef, exists := schema.Types[tt.expectedEnum]
@@ -481,10 +604,10 @@ func Test_addRecursiveAggregation(t *testing.T) {
 			require.NotNil(t, objDef)
 
 			// First create the groupBy (required by addRecursiveAggregation)
-			addAggregateGroupByObject(schema, objDef)
+			addAggregateGroupByObject(schema, objDef, defaultAggregates)
 
 			// Call the function
-			addRecursiveAggregation(schema, objDef)
+			addRecursiveAggregation(schema, objDef, defaultAggregates)
 
 			// Check expected aggregate fields
 			for _, fieldName := range tt.expectAggFields {
@@ -527,3 +650,41 @@ func buildSchemaWithFields(t *testing.T, typeName string, fields []fieldInfo) *a
 	return buildTestSchema(t, sdl)
 }
 
+// Test_addAggregateInputs tests the having and ordering inputs of aggregates
+func Test_addAggregateInputs(t *testing.T) {
+	schema := buildTestSchema(t, `
+		type Product {
+			id: ID!
+			price: Float!
+			createdAt: DateTime
+			tags: [String]
+		}
+	`)
+	aggDef := addAggregateObject(schema, schema.Types["Product"], defaultAggregates)
+	require.NotNil(t, aggDef)
+
+	having := addAggregateHavingInput(schema, "Product", aggDef)
+	assert.Equal(t, "_ProductAggregateHaving", having.Name)
+	assert.Equal(t, ast.InputObject, having.Kind)
+	assert.Equal(t, "IntComparator", having.Fields.ForName("count").Type.Name())
+	assert.Nil(t, having.Fields.ForName("group"))
+	require.NotNil(t, having.Fields.ForName("sum"))
+	sumHaving := schema.Types[having.Fields.ForName("sum").Type.Name()]
+	require.NotNil(t, sumHaving)
+	assert.Equal(t, "_ProductSumHaving", sumHaving.Name)
+	assert.Equal(t, "FloatComparator", sumHaving.Fields.ForName("price").Type.Name())
+	maxHaving := schema.Types[having.Fields.ForName("max").Type.Name()]
+	require.NotNil(t, maxHaving)
+	assert.Equal(t, "DateTimeComparator", maxHaving.Fields.ForName("createdAt").Type.Name())
+	assert.Nil(t, maxHaving.Fields.ForName("tags"))
+
+	ordering := addAggregateOrderingInput(schema, "Product", aggDef)
+	assert.Equal(t, "_ProductAggregateOrdering", ordering.Name)
+	assert.Equal(t, "_OrderingTypes", ordering.Fields.ForName("count").Type.Name())
+	avgOrdering := schema.Types[ordering.Fields.ForName("avg").Type.Name()]
+	require.NotNil(t, avgOrdering)
+	assert.Equal(t, "_OrderingTypes", avgOrdering.Fields.ForName("price").Type.Name())
+
+	// inputs are only created once
+	assert.Same(t, having, addAggregateHavingInput(schema, "Product", aggDef))
+}
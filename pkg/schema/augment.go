@@ -34,6 +34,13 @@ func addRecursive(s *ast.Schema, obj *ast.Definition, fieldStopCase string, augm
 		return nil
 	}
 	for _, f := range obj.Fields {
+		// typed JSON objects may nest arrays of objects at any depth, so they are walked separately
+		if GetJSONDirective(f) != nil && s.Types[GetType(f.Type).Name()].IsCompositeType() {
+			if err := addRecursiveJSON(s, obj, f, fieldStopCase, augmenter, append(visited, obj)...); err != nil {
+				return err
+			}
+			continue
+		}
 		// avoid recurse and adding to internal objects
 		if skipAugment(f, fieldStopCase) || !IsListType(f.Type) {
 			log.Printf("skipping field %s@%s for %s\n", f.Name, obj.Name, fieldStopCase)
@@ -55,6 +62,30 @@ func addRecursive(s *ast.Schema, obj *ast.Definition, fieldStopCase string, augm
 	return nil
 }
 
+// addRecursiveJSON adds the augmenter to the typed JSON field if it's a list of objects, and to all lists of objects
+// nested in its type, i.e. the variants of attributes: {variants: [Variant]} @json(column: "attributes")
+func addRecursiveJSON(s *ast.Schema, obj *ast.Definition, field *ast.FieldDefinition, fieldStopCase string, augmenter FieldAugmenter, visited ...*ast.Definition) error {
+	if IsListType(field.Type) && !skipAugment(field, fieldStopCase) {
+		if err := augmenter(s, obj, field); err != nil {
+			log.Printf("error augmenting json field %s@%s: %s\n", field.Name, obj.Name, err)
+			return err
+		}
+	}
+	fieldType := s.Types[GetType(field.Type).Name()]
+	if hasVisited(fieldType, visited) {
+		return nil
+	}
+	for _, f := range fieldType.Fields {
+		if !s.Types[GetType(f.Type).Name()].IsCompositeType() {
+			continue
+		}
+		if err := addRecursiveJSON(s, fieldType, f, fieldStopCase, augmenter, append(visited, fieldType)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // hasVisited checks if the *ast.Definition has been visited already
 func hasVisited(obj *ast.Definition, visited []*ast.Definition) bool {
 	for _, v := range visited {
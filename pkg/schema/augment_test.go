@@ -46,6 +46,11 @@ func generateTestRunner(t *testing.T, tc *generateTestCase, augmenters ...Augmen
 		Input:   fastgql,
 		BuiltIn: false,
 	})
+	scalars, err := scalarsSource(cfg.Sources)
+	require.NoError(t, err)
+	if scalars != nil {
+		cfg.Sources = append(cfg.Sources, scalars)
+	}
 	assert.Nil(t, cfg.LoadSchema())
 	sources, err := NewFastGQLPlugin("", "", false).CreateAugmented(cfg.Schema, append(augmenters, tc.Augmenter...)...)
 	assert.Nil(t, err)
@@ -241,6 +246,34 @@ func Test_addRecursive(t *testing.T) {
 			expectedCalls: 0, // Scalar lists are skipped
 			expectError:   false,
 		},
+		{
+			name: "recurses_into_json_objects",
+			schemaDefinition: `
+				type Product {
+					id: ID!
+					attributes: Attributes @json(column: "attributes")
+					reviews: [Review] @json(column: "reviews")
+				}
+				type Attributes {
+					color: String
+					details: Details
+					variants: [Variant]
+				}
+				type Details {
+					parts: [Variant]
+				}
+				type Variant {
+					sku: String
+				}
+				type Review {
+					rating: Int
+				}
+			`,
+			typeName:      "Product",
+			fieldStopCase: "test",
+			expectedCalls: 3, // reviews, attributes.variants and attributes.details.parts
+			expectError:   false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -313,3 +346,86 @@ func Test_hasVisited(t *testing.T) {
 		})
 	}
 }
+
+// Test_ComputedFields tests computed fields are filtered, ordered and grouped by, but not written or aggregated
+func Test_ComputedFields(t *testing.T) {
+	s := buildTestSchema(t, `
+		type Person @generateFilterInput @generateMutations {
+			id: Int!
+			firstName: String
+			fullName: String @computed(sql: "first_name || ' ' || last_name")
+			ageInDays: Int @computed(function: "app.age_in_days")
+		}
+		type Query {
+			people: [Person] @generate
+		}
+	`)
+	for _, a := range (&FastGqlPlugin{}).augmenters() {
+		require.NoError(t, a(s))
+	}
+	assert.NotNil(t, s.Types["PersonFilterInput"].Fields.ForName("fullName"))
+	assert.NotNil(t, s.Types["PersonOrdering"].Fields.ForName("ageInDays"))
+	assert.NotNil(t, s.Types["PersonGroupBy"].EnumValues.ForName("FULL_NAME"))
+	assert.Nil(t, s.Types["createPeople"].Fields.ForName("fullName"))
+	assert.Nil(t, s.Types["updatePeople"].Fields.ForName("ageInDays"))
+	assert.Nil(t, s.Types["_PersonMax"].Fields.ForName("ageInDays"))
+	assert.NotNil(t, s.Types["_PersonMax"].Fields.ForName("id"))
+}
+
+func TestGetComputedDirective(t *testing.T) {
+	field := &ast.FieldDefinition{Name: "fullName", Directives: ast.DirectiveList{{
+		Name:      "computed",
+		Arguments: ast.ArgumentList{{Name: "sql", Value: &ast.Value{Kind: ast.StringValue, Raw: "first_name"}}},
+	}}}
+	assert.Equal(t, &ComputedDirective{SQL: "first_name"}, GetComputedDirective(field))
+	assert.Nil(t, GetComputedDirective(&ast.FieldDefinition{Name: "id"}))
+}
+
+// Test_FunctionFields tests @function fields keep their arguments on the aggregate field and views reject mutations
+func Test_FunctionFields(t *testing.T) {
+	s := buildTestSchema(t, `
+		type Product @table(name: "products") @generateFilterInput {
+			id: Int!
+			name: String
+		}
+		type Query {
+			searchProducts(term: String!, minPrice: Float): [Product] @generate @function(name: "search_products", schema: "app")
+		}
+	`)
+	for _, a := range (&FastGqlPlugin{}).augmenters() {
+		require.NoError(t, a(s))
+	}
+	field := s.Query.Fields.ForName("searchProducts")
+	assert.Equal(t, &FunctionDirective{Name: "search_products", Schema: "app"}, GetFunctionDirective(field))
+	args := FunctionArguments(field)
+	require.Len(t, args, 2)
+	assert.Equal(t, "term", args[0].Name)
+	assert.Equal(t, "minPrice", args[1].Name)
+	aggregate := s.Query.Fields.ForName("_searchProductsAggregate")
+	require.NotNil(t, aggregate)
+	assert.Equal(t, "term", aggregate.Arguments[0].Name)
+	assert.NotNil(t, aggregate.Arguments.ForName("filter"))
+	assert.Nil(t, GetFunctionDirective(&ast.FieldDefinition{Name: "products"}))
+
+	view := buildTestSchema(t, `
+		type ProductStats @table(name: "product_stats", view: true) @generateMutations {
+			id: Int!
+		}
+		type Query {
+			productStats: [ProductStats] @generate
+		}
+	`)
+	assert.ErrorContains(t, MutationsAugmenter(view), "read-only view")
+}
+
+func TestGetSQLMutationDirective(t *testing.T) {
+	field := &ast.FieldDefinition{Name: "approveOrder", Directives: ast.DirectiveList{{
+		Name: "sqlMutation",
+		Arguments: ast.ArgumentList{
+			{Name: "function", Value: &ast.Value{Kind: ast.StringValue, Raw: "approve_order"}},
+			{Name: "schema", Value: &ast.Value{Kind: ast.StringValue, Raw: "app"}},
+		},
+	}}}
+	assert.Equal(t, &FunctionDirective{Name: "approve_order", Schema: "app"}, GetSQLMutationDirective(field))
+	assert.Nil(t, GetSQLMutationDirective(&ast.FieldDefinition{Name: "createOrders"}))
+}
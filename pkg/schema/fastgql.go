@@ -11,6 +11,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/iancoleman/strcase"
 	"github.com/spf13/cast"
 
 	"github.com/99designs/gqlgen/codegen"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/99designs/gqlgen/codegen/config"
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
 )
 
 var (
@@ -28,17 +30,35 @@ var (
 	//go:embed server.gotpl
 	fastGqlServerTpl  string
 	FastGQLDirectives = []string{tableDirectiveName, generateDirectiveName, "generateFilterInput", "isInterfaceFilter",
-		skipGenerateDirectiveName, "generateMutations", jsonDirectiveName, relationDirectiveName}
-	defaultAugmenters = []Augmenter{
-		MutationsAugmenter,
-		PaginationAugmenter,
-		OrderByAugmenter,
-		AggregationAugmenter,
-		FilterInputAugmenter,
-		FilterArgAugmenter,
-	}
+		skipGenerateDirectiveName, "generateMutations", jsonDirectiveName, relationDirectiveName,
+		fastgqlFieldDirectiveName, searchableDirectiveName, scalarDirectiveName, computedDirectiveName, functionDirectiveName,
+		sqlMutationDirectiveName, primaryDirectiveName, streamDirectiveName}
+)
+
+const (
+	// geoJSONModel is the Go type of the Geometry and Geography scalars
+	geoJSONModel = "github.com/roneli/fastgql/pkg/scalars.GeoJSON"
+	// scalarsSourceName is the name of the source declaring the fastgql scalars missing from the user schema
+	scalarsSourceName = "fastgql_scalars.graphql"
 )
 
+// fastgqlScalar is a scalar used by the fastgql comparators, it's declared unless the user schema declares it and
+// bound to model unless the user bound it to their own model
+type fastgqlScalar struct {
+	name        string
+	description string
+	model       string
+}
+
+var fastgqlScalars = []fastgqlScalar{
+	{name: GeometryScalarName, description: "PostGIS geometry, values are GeoJSON objects", model: geoJSONModel},
+	{name: GeographyScalarName, description: "PostGIS geography, values are GeoJSON objects in WGS 84, distances are in meters", model: geoJSONModel},
+	{name: DateScalarName, description: "Calendar date i.e. 2006-01-02", model: "github.com/roneli/fastgql/pkg/scalars.Date"},
+	{name: DateTimeScalarName, description: "RFC 3339 timestamp i.e. 2006-01-02T15:04:05Z", model: "github.com/99designs/gqlgen/graphql.Time"},
+	{name: TimeOfDayScalarName, description: "Time of day i.e. 15:04:05", model: "github.com/roneli/fastgql/pkg/scalars.TimeOfDay"},
+	{name: IntervalScalarName, description: "Postgres interval i.e. \"3 days\" or \"2 hours 30 minutes\"", model: "github.com/99designs/gqlgen/graphql.String"},
+}
+
 const defaultResolverTemplate = `
 {{ reserveImport "context"  }}
 {{ reserveImport "github.com/roneli/fastgql/pkg/execution/builders" }}
@@ -55,14 +75,57 @@ type FastGqlPlugin struct {
 	generateServer bool
 	serverFilename string
 	codgen         *codegen.Data
+	// aggregates are the custom aggregates added to the generated aggregate payloads
+	aggregates []Aggregate
+	// scalars declare how fields of custom scalars are filtered, ordered and aggregated
+	scalars []Scalar
+}
+
+// Option configures the FastGqlPlugin
+type Option func(f *FastGqlPlugin)
+
+// WithAggregates adds custom aggregates to the generated aggregate payloads, replacing default aggregates of the same
+// name. Each must have a matching builders.AggregateFunc in the builders.Config CustomAggregates.
+func WithAggregates(aggregates ...Aggregate) Option {
+	return func(f *FastGqlPlugin) {
+		f.aggregates = append(f.aggregates, aggregates...)
+	}
+}
+
+// WithScalars declares how fields of custom scalars are filtered, ordered and aggregated, as if the scalars declared in
+// the schema had the @scalar directive. A @scalar directive in the schema takes precedence.
+func WithScalars(scalars ...Scalar) Option {
+	return func(f *FastGqlPlugin) {
+		f.scalars = append(f.scalars, scalars...)
+	}
 }
 
-func NewFastGQLPlugin(rootDir, serverFileName string, generateServer bool) *FastGqlPlugin {
-	return &FastGqlPlugin{
+func NewFastGQLPlugin(rootDir, serverFileName string, generateServer bool, opts ...Option) *FastGqlPlugin {
+	f := &FastGqlPlugin{
 		rootDirectory:  rootDir,
 		generateServer: generateServer,
 		serverFilename: serverFileName,
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// augmenters returns the augmenters applied when CreateAugmented is called without augmenters
+func (f *FastGqlPlugin) augmenters() []Augmenter {
+	return []Augmenter{
+		NewScalarsAugmenter(f.scalars...),
+		TableAugmenter,
+		MutationsAugmenter,
+		PaginationAugmenter,
+		OrderByAugmenter,
+		NewAggregationAugmenter(f.aggregates...),
+		FilterInputAugmenter,
+		FilterArgAugmenter,
+		SearchAugmenter,
+		FederationAugmenter,
+	}
 }
 
 func (f *FastGqlPlugin) Name() string {
@@ -74,6 +137,13 @@ func (f *FastGqlPlugin) MutateConfig(c *config.Config) error {
 	for _, d := range FastGQLDirectives {
 		c.Directives[d] = config.DirectiveConfig{SkipRuntime: true}
 	}
+	// bind fastgql scalars to their Go types, unless the user bound them to their own model
+	for _, scalar := range fastgqlScalars {
+		if c.Schema == nil || c.Schema.Types[scalar.name] == nil || c.Models.Exists(scalar.name) {
+			continue
+		}
+		c.Models[scalar.name] = config.TypeMapEntry{Model: config.StringList{scalar.model}}
+	}
 	return nil
 }
 
@@ -119,29 +189,53 @@ func (f *FastGqlPlugin) GenerateCode(data *codegen.Data) error {
 
 func (f *FastGqlPlugin) Implement(_ string, field *codegen.Field) string {
 	buf := &bytes.Buffer{}
-	if field.TypeReference.Definition.Directives.ForName(generateDirectiveName) != nil {
+	loader := newFastGQLLoader(field)
+	if loader == nil && field.TypeReference.Definition.Directives.ForName(generateDirectiveName) != nil {
+		return `panic(fmt.Errorf("not implemented"))`
+	}
+	if loader == nil && (field.TypeReference.Definition.IsLeafType() || field.TypeReference.Definition.IsInputType()) {
 		return `panic(fmt.Errorf("not implemented"))`
 	}
-	if field.TypeReference.Definition.IsLeafType() || field.TypeReference.Definition.IsInputType() {
+	entity := newFastGQLEntity(field)
+	if entity == nil && field.Object != nil && field.Object.Name == "Entity" && strings.HasPrefix(field.Name, "find") {
+		// only batched entity resolvers of @table types are generated
 		return `panic(fmt.Errorf("not implemented"))`
 	}
 	baseFuncs := templates.Funcs()
 	baseFuncs["hasSuffix"] = strings.HasSuffix
 	baseFuncs["hasPrefix"] = strings.HasPrefix
 	baseFuncs["deref"] = deref
+	baseFuncs["join"] = strings.Join
 	var implementors = make(map[string]codegen.InterfaceImplementor)
 	var fieldType = field.TypeReference.GO
 	var implTypeName = "typename"
 	interfaces, ok := f.codgen.Interfaces[field.Type.Name()]
 	if ok {
 		implTypeName = getTypeName(field.Directives)
+		if d := field.TypeReference.Definition.Directives.ForName("typename"); d != nil {
+			implTypeName = cast.ToString(GetDirectiveValue(d, "name"))
+		}
 		fieldType = interfaces.Type
 		for _, implementor := range interfaces.Implementors {
 			implementors[implementor.Name] = implementor
 		}
+	} else if isMutationField(field.Name) && GetSQLMutationDirective(field.FieldDefinition) == nil {
+		// mutation payloads returning interfaces stored in a single table need their rows scanned by typename
+		for _, pf := range field.TypeReference.Definition.Fields {
+			payloadInterface, ok := f.codgen.Interfaces[pf.Type.Name()]
+			if !ok || payloadInterface.Definition.Directives.ForName("typename") == nil {
+				continue
+			}
+			implTypeName = cast.ToString(GetDirectiveValue(payloadInterface.Definition.Directives.ForName("typename"), "name"))
+			for _, implementor := range payloadInterface.Implementors {
+				implementors[implementor.Name] = implementor
+			}
+			break
+		}
 	}
 
-	fResolver := fastGQLResolver{field, fieldType, implementors, implTypeName, "postgres"}
+	sqlMutation := GetSQLMutationDirective(field.FieldDefinition) != nil
+	fResolver := fastGQLResolver{field, fieldType, implementors, implTypeName, "postgres", loader, sqlMutation, entity}
 	t := template.New("").Funcs(baseFuncs)
 	t, err := t.New("fastgql.tpl").Parse(fastGqlTpl)
 	if err != nil {
@@ -157,7 +251,7 @@ func (f *FastGqlPlugin) Implement(_ string, field *codegen.Field) string {
 // so gqlgen can generate an augmented fastGQL server
 func (f *FastGqlPlugin) CreateAugmented(schema *ast.Schema, augmenters ...Augmenter) ([]*ast.Source, error) {
 	if len(augmenters) == 0 {
-		augmenters = defaultAugmenters
+		augmenters = f.augmenters()
 	}
 	for _, a := range augmenters {
 		if err := a(schema); err != nil {
@@ -168,12 +262,143 @@ func (f *FastGqlPlugin) CreateAugmented(schema *ast.Schema, augmenters ...Augmen
 	return Format(f.rootDirectory, schema), nil
 }
 
+// scalarsSource returns a source declaring the fastgql scalars that none of the sources declare, or nil if all of
+// them are declared. It's added before the schema is loaded, so user schemas can declare these scalars themselves.
+func scalarsSource(sources []*ast.Source) (*ast.Source, error) {
+	declared := make(map[string]bool)
+	for _, src := range sources {
+		doc, err := parser.ParseSchema(src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", src.Name, err)
+		}
+		for _, def := range doc.Definitions {
+			declared[def.Name] = true
+		}
+	}
+	var sb strings.Builder
+	for _, scalar := range fastgqlScalars {
+		if declared[scalar.name] {
+			continue
+		}
+		fmt.Fprintf(&sb, "%q\nscalar %s\n", scalar.description, scalar.name)
+	}
+	if sb.Len() == 0 {
+		return nil, nil
+	}
+	return &ast.Source{Name: scalarsSourceName, Input: sb.String(), BuiltIn: true}, nil
+}
+
+// isMutationField returns true if the field is a generated create, update or delete mutation
+func isMutationField(name string) bool {
+	return strings.HasPrefix(name, "create") || strings.HasPrefix(name, "delete") || strings.HasPrefix(name, "update")
+}
+
 type fastGQLResolver struct {
 	Field                *codegen.Field
 	FieldType            types.Type
 	Implementors         map[string]codegen.InterfaceImplementor
 	ImplementorsTypeName string
 	Dialect              string
+	Loader               *fastGQLLoader
+	// SQLMutation is true if the field is a @sqlMutation field, resolved by calling its function
+	SQLMutation bool
+	Entity      *fastGQLEntity
+}
+
+// fastGQLLoader describes a batched resolver generated for @fastgqlField(skipSelect) fields, the resolver loads
+// the field by the parent keys using execution.Load so sibling objects are resolved in a single batch.
+type fastGQLLoader struct {
+	// Name of the loader i.e. <Object>.<field>
+	Name string
+	// KeyType is the Go type of the loader keys
+	KeyType types.Type
+	// Key is the Go expression of the parent key passed to the loader
+	Key string
+	// NilChecks are expressions that resolve to nil values instead of loading if they are nil
+	NilChecks []string
+}
+
+// newFastGQLLoader returns the loader of the field if it's marked with @fastgqlField(skipSelect) and all its
+// keys are bound to the parent model fields, otherwise nil is returned.
+func newFastGQLLoader(field *codegen.Field) *fastGQLLoader {
+	d := GetFastgqlFieldDirective(field.FieldDefinition)
+	if d == nil || !d.SkipSelect || len(d.Keys) == 0 || field.Object == nil {
+		return nil
+	}
+	keyFields := make([]*codegen.Field, 0, len(d.Keys))
+	for _, k := range d.Keys {
+		var keyField *codegen.Field
+		for _, f := range field.Object.Fields {
+			if f.Name == strcase.ToLowerCamel(k) && f.GoFieldType == codegen.GoFieldVariable {
+				keyField = f
+				break
+			}
+		}
+		if keyField == nil {
+			return nil
+		}
+		keyFields = append(keyFields, keyField)
+	}
+	loader := &fastGQLLoader{Name: field.Object.Name + "." + field.Name}
+	if len(keyFields) > 1 {
+		keys := make([]string, 0, len(keyFields))
+		for _, f := range keyFields {
+			keys = append(keys, "obj."+f.GoFieldName)
+		}
+		loader.KeyType = types.Typ[types.String]
+		loader.Key = fmt.Sprintf("execution.CompositeKey(%s)", strings.Join(keys, ", "))
+		return loader
+	}
+	keyField := keyFields[0]
+	loader.KeyType = keyField.TypeReference.GO
+	loader.Key = "obj." + keyField.GoFieldName
+	if ptr, ok := keyField.TypeReference.GO.(*types.Pointer); ok {
+		loader.KeyType = ptr.Elem()
+		loader.NilChecks = append(loader.NilChecks, loader.Key)
+		loader.Key = "*" + loader.Key
+	}
+	return loader
+}
+
+// fastGQLEntity describes a batched Apollo Federation entity resolver generated for @key types with a @table
+// directive, the resolver loads all representations of the type in a single query using execution.Entities.
+type fastGQLEntity struct {
+	// TypeName is the entity type name
+	TypeName string
+	// KeyFields are the key field names of the entity
+	KeyFields []string
+	// Reps is the name of the representations argument
+	Reps string
+	// Keys are the Go expressions of the key values of a representation rep
+	Keys []string
+}
+
+// newFastGQLEntity returns the entity of a federation findMany<Type>By<Keys> resolver if its type has a @table
+// directive and all its keys are fields of the type, otherwise nil is returned.
+func newFastGQLEntity(field *codegen.Field) *fastGQLEntity {
+	if field.Object == nil || field.Object.Name != "Entity" || !strings.HasPrefix(field.Name, "findMany") || len(field.Args) != 1 {
+		return nil
+	}
+	def := field.TypeReference.Definition
+	if def.Directives.ForName(tableDirectiveName) == nil {
+		return nil
+	}
+	entity := &fastGQLEntity{TypeName: def.Name, Reps: field.Args[0].VarName}
+	for _, f := range field.Args[0].TypeReference.Definition.Fields {
+		var keyField *ast.FieldDefinition
+		for _, df := range def.Fields {
+			if templates.ToGo(df.Name) == f.Name {
+				keyField = df
+				break
+			}
+		}
+		if keyField == nil {
+			return nil
+		}
+		entity.KeyFields = append(entity.KeyFields, keyField.Name)
+		entity.Keys = append(entity.Keys, "rep."+templates.ToGo(f.Name))
+	}
+	return entity
 }
 
 func getTypeName(directives []*codegen.Directive) string {
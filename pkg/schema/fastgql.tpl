@@ -1,10 +1,53 @@
-{{- if or (hasPrefix .Field.Name "create") (hasPrefix .Field.Name "delete") (hasPrefix .Field.Name "update") -}}
+{{- if .Loader -}}
+{{- reserveImport "github.com/roneli/fastgql/pkg/execution" -}}
+{{- range .Loader.NilChecks }}
+if {{.}} == nil {
+    var data {{$.Field.TypeReference.GO | ref}}
+    return data, nil
+}
+{{- end }}
+return execution.Load(ctx, {{.Loader.Name|quote}}, {{.Loader.Key}}, func(ctx context.Context, keys []{{.Loader.KeyType | ref}}) ([]{{.Field.TypeReference.GO | ref}}, error) {
+    // Resolve {{.Loader.Name}} for all keys at once, values must be returned in the same order as keys
+    panic(fmt.Errorf("not implemented: {{.Loader.Name}} batch resolver"))
+})
+{{- else if .Entity -}}
+{{- reserveImport "github.com/roneli/fastgql/pkg/execution" -}}
+keys := make([][]any, len({{.Entity.Reps}}))
+for i, rep := range {{.Entity.Reps}} {
+    keys[i] = []any{ {{- join .Entity.Keys ", " -}} }
+}
+var data {{.Field.TypeReference.GO | ref}}
+if err := execution.Entities(ctx, r.Executor, {{.Entity.TypeName|quote}}, []string{
+{{- range $i, $k := .Entity.KeyFields }}{{if $i}}, {{end}}{{$k|quote}}{{end -}}
+}, keys, &data); err != nil {
+    return nil, err
+}
+return data, nil
+{{- else if .SQLMutation -}}
+var data {{.Field.TypeReference.GO | ref}}
+if err := r.Executor.Mutate(ctx, &data); err != nil {
+    return nil, err
+}
+return data, nil
+{{- else if or (hasPrefix .Field.Name "create") (hasPrefix .Field.Name "delete") (hasPrefix .Field.Name "update") -}}
 var data {{.Field.TypeReference.GO | deref}}
+{{- if .Implementors }}
+{{- reserveImport "reflect" }}
+{{- reserveImport "github.com/roneli/fastgql/pkg/execution" }}
+if err := execution.MutateWithTypes(ctx, r.Executor, &data, map[string]reflect.Type{
+{{- range  $key, $value := .Implementors }}
+    {{$key|quote}}: reflect.TypeOf({{$value.Type | deref}}{}),
+{{- end -}}
+}, {{.ImplementorsTypeName|quote}}); err != nil {
+    return nil, err
+}
+{{- else }}
 if err := r.Executor.Mutate(ctx, &data); err != nil {
     return nil, err
 }
+{{- end }}
 return &data, nil
-{{- else if eq .Field.TypeReference.Definition.Kind  "INTERFACE" -}}
+{{- else if or (eq .Field.TypeReference.Definition.Kind "INTERFACE") (eq .Field.TypeReference.Definition.Kind "UNION") -}}
 {{- reserveImport "reflect" -}}
 var data {{.Field.TypeReference.GO | ref}}
 if err := r.Executor.QueryWithTypes(ctx, &data, map[string]reflect.Type{
@@ -1,10 +1,15 @@
 package schema
 
 import (
+	"go/types"
 	"testing"
 
+	"github.com/99designs/gqlgen/codegen"
 	"github.com/99designs/gqlgen/codegen/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
 )
 
 func Test_GenerateNoFastGql(t *testing.T) {
@@ -17,3 +22,240 @@ func Test_GenerateNoFastGql(t *testing.T) {
 	assert.Nil(t, cfg.LoadSchema())
 	assert.Len(t, srcs, 1)
 }
+
+func Test_newFastGQLLoader(t *testing.T) {
+	idField := &codegen.Field{
+		FieldDefinition: &ast.FieldDefinition{Name: "id"},
+		GoFieldType:     codegen.GoFieldVariable,
+		GoFieldName:     "ID",
+		TypeReference:   &config.TypeReference{GO: types.Typ[types.Int]},
+	}
+	userIDField := &codegen.Field{
+		FieldDefinition: &ast.FieldDefinition{Name: "userId"},
+		GoFieldType:     codegen.GoFieldVariable,
+		GoFieldName:     "UserID",
+		TypeReference:   &config.TypeReference{GO: types.NewPointer(types.Typ[types.Int])},
+	}
+	object := &codegen.Object{
+		Definition: &ast.Definition{Name: "Post"},
+		Fields:     []*codegen.Field{idField, userIDField},
+	}
+	newField := func(directive string) *codegen.Field {
+		doc, err := parser.ParseSchema(&ast.Source{Input: "type Post { author: String " + directive + " }"})
+		require.NoError(t, err)
+		return &codegen.Field{
+			FieldDefinition: doc.Definitions[0].Fields[0],
+			Object:          object,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		directive string
+		expected  *fastGQLLoader
+	}{
+		{
+			name:      "no_directive",
+			directive: "",
+		},
+		{
+			name:      "no_keys",
+			directive: "@fastgqlField",
+		},
+		{
+			name:      "skip_select_false",
+			directive: `@fastgqlField(skipSelect: false, keys: ["id"])`,
+		},
+		{
+			name:      "unknown_key",
+			directive: `@fastgqlField(keys: ["author_id"])`,
+		},
+		{
+			name:      "single_key",
+			directive: `@fastgqlField(keys: ["id"])`,
+			expected:  &fastGQLLoader{Name: "Post.author", KeyType: types.Typ[types.Int], Key: "obj.ID"},
+		},
+		{
+			name:      "nullable_key",
+			directive: `@fastgqlField(keys: ["user_id"])`,
+			expected:  &fastGQLLoader{Name: "Post.author", KeyType: types.Typ[types.Int], Key: "*obj.UserID", NilChecks: []string{"obj.UserID"}},
+		},
+		{
+			name:      "relation_keys",
+			directive: `@fastgqlField @relation(type: ONE_TO_ONE, fields: ["user_id"], references: ["id"])`,
+			expected:  &fastGQLLoader{Name: "Post.author", KeyType: types.Typ[types.Int], Key: "*obj.UserID", NilChecks: []string{"obj.UserID"}},
+		},
+		{
+			name:      "composite_keys",
+			directive: `@fastgqlField(keys: ["id", "user_id"])`,
+			expected:  &fastGQLLoader{Name: "Post.author", KeyType: types.Typ[types.String], Key: "execution.CompositeKey(obj.ID, obj.UserID)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, newFastGQLLoader(newField(tt.directive)))
+		})
+	}
+}
+
+func Test_newFastGQLEntity(t *testing.T) {
+	doc, err := parser.ParseSchema(&ast.Source{Input: `
+		type User @table(name: "users") { id: Int! orgId: Int! name: String }
+		type Comment { id: Int! }
+		input UserByIDAndOrgIDsInput { ID: Int! OrgID: Int! }
+		input UserByOrgsInput { Orgs: String }
+	`})
+	require.NoError(t, err)
+	user, comment, input, unknownInput := doc.Definitions[0], doc.Definitions[1], doc.Definitions[2], doc.Definitions[3]
+	newField := func(object, name string, def, inputDef *ast.Definition) *codegen.Field {
+		return &codegen.Field{
+			FieldDefinition: &ast.FieldDefinition{Name: name},
+			Object:          &codegen.Object{Definition: &ast.Definition{Name: object}},
+			TypeReference:   &config.TypeReference{Definition: def},
+			Args: []*codegen.FieldArgument{{
+				ArgumentDefinition: &ast.ArgumentDefinition{Name: "reps"},
+				VarName:            "reps",
+				TypeReference:      &config.TypeReference{Definition: inputDef},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		field    *codegen.Field
+		expected *fastGQLEntity
+	}{
+		{
+			name:  "batched_entity",
+			field: newField("Entity", "findManyUserByIDAndOrgIDs", user, input),
+			expected: &fastGQLEntity{
+				TypeName:  "User",
+				KeyFields: []string{"id", "orgId"},
+				Reps:      "reps",
+				Keys:      []string{"rep.ID", "rep.OrgID"},
+			},
+		},
+		{
+			name:  "not_entity_resolver",
+			field: newField("Query", "findManyUserByIDAndOrgIDs", user, input),
+		},
+		{
+			name:  "single_entity_resolver",
+			field: newField("Entity", "findUserByIDAndOrgID", user, input),
+		},
+		{
+			name:  "no_table",
+			field: newField("Entity", "findManyCommentByIDs", comment, input),
+		},
+		{
+			name:  "unknown_key_field",
+			field: newField("Entity", "findManyUserByOrgs", user, unknownInput),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, newFastGQLEntity(tt.field))
+		})
+	}
+}
+
+// loadFastGQLSchema loads the fastgql schema and the user schema along with the fastgql scalars it doesn't declare
+func loadFastGQLSchema(t *testing.T, schemaDefinition string) *config.Config {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Sources = []*ast.Source{{Name: "schema.graphql", Input: FastGQLSchema + schemaDefinition}}
+	scalars, err := scalarsSource(cfg.Sources)
+	require.NoError(t, err)
+	if scalars != nil {
+		cfg.Sources = append(cfg.Sources, scalars)
+	}
+	require.NoError(t, cfg.LoadSchema())
+	return cfg
+}
+
+func TestFastGqlPlugin_MutateConfig_GeoScalars(t *testing.T) {
+	cfg := loadFastGQLSchema(t, `
+		type Place {
+			location: Geometry
+		}
+		type Query {
+			places: [Place]
+		}
+	`)
+	cfg.Models["Geography"] = config.TypeMapEntry{Model: config.StringList{"example.com/geo.Geography"}}
+
+	require.NoError(t, (&FastGqlPlugin{}).MutateConfig(cfg))
+	assert.Equal(t, config.StringList{geoJSONModel}, cfg.Models["Geometry"].Model)
+	assert.Equal(t, config.StringList{"example.com/geo.Geography"}, cfg.Models["Geography"].Model, "user models aren't overridden")
+}
+
+func TestFastGqlPlugin_MutateConfig_TemporalScalars(t *testing.T) {
+	cfg := loadFastGQLSchema(t, `
+		scalar Time
+		type Event {
+			day: Date
+			createdAt: DateTime
+			startsAt: TimeOfDay
+			updatedAt: Time
+			duration: Interval
+		}
+		type Query {
+			events: [Event]
+		}
+	`)
+	// gqlgen binds Time to a timestamp by default
+	cfg.Models["Time"] = config.TypeMapEntry{Model: config.StringList{"github.com/99designs/gqlgen/graphql.Time"}}
+	cfg.Models["Date"] = config.TypeMapEntry{Model: config.StringList{"example.com/civil.Date"}}
+
+	require.NoError(t, (&FastGqlPlugin{}).MutateConfig(cfg))
+	assert.Equal(t, config.StringList{"github.com/roneli/fastgql/pkg/scalars.TimeOfDay"}, cfg.Models["TimeOfDay"].Model)
+	assert.Equal(t, config.StringList{"github.com/99designs/gqlgen/graphql.Time"}, cfg.Models["Time"].Model, "gqlgen's Time is left a timestamp")
+	assert.Equal(t, config.StringList{"github.com/99designs/gqlgen/graphql.Time"}, cfg.Models["DateTime"].Model)
+	assert.Equal(t, config.StringList{"github.com/99designs/gqlgen/graphql.String"}, cfg.Models["Interval"].Model)
+	assert.Equal(t, config.StringList{"example.com/civil.Date"}, cfg.Models["Date"].Model, "user models aren't overridden")
+}
+
+func Test_scalarsSource(t *testing.T) {
+	src, err := scalarsSource([]*ast.Source{{Name: "schema.graphql", Input: `
+		scalar Date
+		"""user defined interval"""
+		scalar Interval
+		type Query {
+			today: Date
+		}
+	`}})
+	require.NoError(t, err)
+	require.NotNil(t, src)
+	assert.True(t, src.BuiltIn)
+	assert.Contains(t, src.Input, "scalar Geometry")
+	assert.Contains(t, src.Input, "scalar DateTime")
+	assert.Contains(t, src.Input, "scalar TimeOfDay")
+	assert.NotContains(t, src.Input, "scalar Date\n", "scalars declared by the user schema aren't declared again")
+	assert.NotContains(t, src.Input, "scalar Interval")
+
+	declared := []*ast.Source{{Name: "schema.graphql", Input: `
+		scalar Geometry
+		scalar Geography
+		scalar Date
+		scalar DateTime
+		scalar TimeOfDay
+		scalar Interval
+	`}}
+	src, err = scalarsSource(declared)
+	require.NoError(t, err)
+	assert.Nil(t, src)
+
+	_, err = scalarsSource([]*ast.Source{{Name: "broken.graphql", Input: "type {"}})
+	require.Error(t, err)
+}
+
+func TestFastGqlPlugin_WithScalars(t *testing.T) {
+	s := buildTestSchema(t, scalarsTestSchema)
+	plugin := NewFastGQLPlugin("", "", false, WithScalars(Scalar{Name: "Money", Operators: []string{"eq", "gt"}, Cast: "money"}))
+	_, err := plugin.CreateAugmented(s)
+	require.NoError(t, err)
+
+	comparator := s.Types["MoneyComparator"]
+	require.NotNil(t, comparator, "a comparator is generated for scalars declared with WithScalars")
+	assert.Len(t, comparator.Fields, 2)
+}
@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/99designs/gqlgen/plugin/federation"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	keyDirectiveName            = "key"
+	entityResolverDirectiveName = "entityResolver"
+)
+
+// FederationAugmenter batches the Apollo Federation entity resolvers of @key types with a @table directive, adding
+// @entityResolver(multi: true) so all representations of a type are resolved with a single query.
+// Types that already define @entityResolver are left as is.
+func FederationAugmenter(s *ast.Schema) error {
+	if s.Directives[keyDirectiveName] == nil {
+		return nil
+	}
+	for _, def := range s.Types {
+		if def.Kind != ast.Object || def.Directives.ForName(keyDirectiveName) == nil || def.Directives.ForName(tableDirectiveName) == nil {
+			continue
+		}
+		if def.Directives.ForName(entityResolverDirectiveName) != nil {
+			continue
+		}
+		log.Printf("adding batched entity resolver to %s\n", def.Name)
+		def.Directives = append(def.Directives, &ast.Directive{
+			Name:       entityResolverDirectiveName,
+			Arguments:  ast.ArgumentList{{Name: "multi", Value: &ast.Value{Kind: ast.BooleanValue, Raw: "true"}}},
+			Definition: entityResolverDirective(s),
+		})
+	}
+	return nil
+}
+
+// entityResolverDirective returns the @entityResolver directive definition, adding it to the schema if it's missing
+func entityResolverDirective(s *ast.Schema) *ast.DirectiveDefinition {
+	if d, ok := s.Directives[entityResolverDirectiveName]; ok {
+		return d
+	}
+	d := &ast.DirectiveDefinition{
+		Name:      entityResolverDirectiveName,
+		Arguments: ast.ArgumentDefinitionList{{Name: "multi", Type: ast.NamedType("Boolean", nil)}},
+		Locations: []ast.DirectiveLocation{ast.LocationObject},
+	}
+	s.Directives[entityResolverDirectiveName] = d
+	return d
+}
+
+// federationSources returns the federation directives and scalars if federation is enabled in the config, they
+// are added before the schema is loaded so @key can be used in the augmented schema
+func federationSources(cfg *config.Config) ([]*ast.Source, error) {
+	if !cfg.Federation.IsDefined() {
+		return nil, nil
+	}
+	f, err := federation.New(cfg.Federation.Version, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create federation plugin: %w", err)
+	}
+	return f.InjectSourcesEarly()
+}
@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FederationAugmenter(t *testing.T) {
+	s := buildTestSchema(t, `
+		directive @key(fields: String!) repeatable on OBJECT | INTERFACE
+		type User @table(name: "users") @key(fields: "id") {
+			id: Int!
+			name: String
+		}
+		type Post @table(name: "posts") @key(fields: "id") @entityResolver(multi: false) {
+			id: Int!
+		}
+		type Comment @key(fields: "id") {
+			id: Int!
+		}
+		directive @entityResolver(multi: Boolean) on OBJECT
+		type Query {
+			users: [User] @generate
+		}
+	`)
+	require.NoError(t, FederationAugmenter(s))
+	d := s.Types["User"].Directives.ForName(entityResolverDirectiveName)
+	require.NotNil(t, d)
+	assert.Equal(t, true, GetDirectiveValue(d, "multi"))
+	assert.Equal(t, false, GetDirectiveValue(s.Types["Post"].Directives.ForName(entityResolverDirectiveName), "multi"))
+	assert.Nil(t, s.Types["Comment"].Directives.ForName(entityResolverDirectiveName))
+
+	t.Run("adds_directive_definition", func(t *testing.T) {
+		s := buildTestSchema(t, `
+			directive @key(fields: String!) repeatable on OBJECT | INTERFACE
+			type User @table(name: "users") @key(fields: "id") {
+				id: Int!
+			}
+			type Query {
+				users: [User] @generate
+			}
+		`)
+		require.NoError(t, FederationAugmenter(s))
+		require.NotNil(t, s.Directives[entityResolverDirectiveName])
+		assert.NotNil(t, s.Types["User"].Directives.ForName(entityResolverDirectiveName))
+	})
+	t.Run("federation_disabled", func(t *testing.T) {
+		s := buildTestSchema(t, `
+			type User @table(name: "users") {
+				id: Int!
+			}
+			type Query {
+				users: [User] @generate
+			}
+		`)
+		require.NoError(t, FederationAugmenter(s))
+		assert.Nil(t, s.Directives[entityResolverDirectiveName])
+	})
+}
+
+func Test_federationSources(t *testing.T) {
+	cfg := config.DefaultConfig()
+	srcs, err := federationSources(cfg)
+	require.NoError(t, err)
+	assert.Empty(t, srcs)
+
+	cfg.Federation = config.PackageConfig{Filename: "federation.go", Version: 2}
+	srcs, err = federationSources(cfg)
+	require.NoError(t, err)
+	require.NotEmpty(t, srcs)
+	assert.True(t, srcs[0].BuiltIn)
+	assert.True(t, strings.Contains(srcs[0].Input, "directive @key"))
+}
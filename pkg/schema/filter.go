@@ -60,6 +60,7 @@ func FilterArgAugmenter(s *ast.Schema) error {
 }
 
 func FilterInputAugmenter(s *ast.Schema) error {
+	addScalarComparators(s)
 	inputs := initInputs(s)
 	for _, input := range inputs {
 		buildFilterInput(s, input.input, input.object)
@@ -112,14 +113,47 @@ func addFilterToQueryFieldArgs(s *ast.Schema, obj *ast.Definition, field *ast.Fi
 	return nil
 }
 
-// resolveScalarOrEnumComparator resolves comparators for scalar and enum types
+// resolveScalarOrEnumComparator resolves comparators for scalar and enum types, enum comparators are generated
+// if they aren't defined in the schema
 func resolveScalarOrEnumComparator(s *ast.Schema, field *ast.FieldDefinition, fieldType *ast.Type) *ast.Definition {
+	if def := s.Types[fieldType.Name()]; def != nil && def.Kind == ast.Enum {
+		return addEnumComparator(s, def, IsListType(field.Type))
+	}
 	if IsListType(field.Type) {
 		return s.Types[fmt.Sprintf("%sListComparator", fieldType.Name())]
 	}
 	return s.Types[fmt.Sprintf("%sComparator", fieldType.Name())]
 }
 
+// addEnumComparator adds the <Enum>Comparator or <Enum>ListComparator input of the enum, unless it's already defined
+func addEnumComparator(s *ast.Schema, enum *ast.Definition, list bool) *ast.Definition {
+	name := fmt.Sprintf("%sComparator", enum.Name)
+	operators := []string{"eq", "neq", "in", "notIn"}
+	if list {
+		name = fmt.Sprintf("%sListComparator", enum.Name)
+		operators = []string{"eq", "neq", "contains", "containedBy", "overlap"}
+	}
+	if def, ok := s.Types[name]; ok {
+		return def
+	}
+	log.Printf("adding comparator %s for enum %s\n", name, enum.Name)
+	comparator := &ast.Definition{
+		Kind:        ast.InputObject,
+		Description: fmt.Sprintf("Comparator for %s", enum.Name),
+		Name:        name,
+	}
+	for _, op := range operators {
+		opType := &ast.Type{NamedType: enum.Name}
+		if list || op == "in" || op == "notIn" {
+			opType = &ast.Type{Elem: &ast.Type{NamedType: enum.Name, NonNull: true}}
+		}
+		comparator.Fields = append(comparator.Fields, &ast.FieldDefinition{Name: op, Type: opType})
+	}
+	comparator.Fields = append(comparator.Fields, &ast.FieldDefinition{Name: "isNull", Type: &ast.Type{NamedType: "Boolean"}})
+	s.Types[name] = comparator
+	return comparator
+}
+
 // resolveJsonPathScalarOrEnumComparator resolves JSONPath-specific comparators for JSON fields
 func resolveJsonPathScalarOrEnumComparator(s *ast.Schema, field *ast.FieldDefinition, fieldType *ast.Type) *ast.Definition {
 	if IsListType(field.Type) {
@@ -250,6 +284,13 @@ func buildFilterInput(s *ast.Schema, input *ast.Definition, object *ast.Definiti
 			})
 		}
 	}
+	if GetSearchableDirective(object) != nil {
+		input.Fields = append(input.Fields, &ast.FieldDefinition{
+			Name:        SearchFilterFieldName,
+			Description: fmt.Sprintf("Full-text search on %s", object.Name),
+			Type:        &ast.Type{NamedType: "String"},
+		})
+	}
 	addLogicalOperators(input, input.Name)
 }
 
@@ -322,6 +322,54 @@ func Test_buildFilterInput(t *testing.T) {
 	}
 }
 
+// Test_addEnumComparator tests comparators are generated for enum fields without hand-written comparators
+func Test_addEnumComparator(t *testing.T) {
+	schema := buildTestSchema(t, `
+		enum Status {
+			OPEN
+			CLOSED
+		}
+		enum Label {
+			BUG
+		}
+		input LabelComparator {
+			eq: Label
+		}
+		type Ticket @generateFilterInput {
+			id: ID!
+			status: Status
+			labels: [Label]
+			label: Label
+		}
+		type Query {
+			tickets: [Ticket]
+		}
+	`)
+	require.NoError(t, FilterInputAugmenter(schema))
+
+	filterInput := schema.Types["TicketFilterInput"]
+	require.NotNil(t, filterInput)
+	assert.Equal(t, "StatusComparator", filterInput.Fields.ForName("status").Type.Name())
+	assert.Equal(t, "LabelListComparator", filterInput.Fields.ForName("labels").Type.Name())
+	assert.Equal(t, "LabelComparator", filterInput.Fields.ForName("label").Type.Name())
+
+	statusComparator := schema.Types["StatusComparator"]
+	require.NotNil(t, statusComparator)
+	assert.Equal(t, ast.InputObject, statusComparator.Kind)
+	assert.Equal(t, "Status", statusComparator.Fields.ForName("eq").Type.String())
+	assert.Equal(t, "[Status!]", statusComparator.Fields.ForName("in").Type.String())
+	assert.Equal(t, "[Status!]", statusComparator.Fields.ForName("notIn").Type.String())
+	assert.Equal(t, "Boolean", statusComparator.Fields.ForName("isNull").Type.String())
+
+	listComparator := schema.Types["LabelListComparator"]
+	require.NotNil(t, listComparator)
+	for _, op := range []string{"eq", "neq", "contains", "containedBy", "overlap"} {
+		require.NotNil(t, listComparator.Fields.ForName(op), "expected list operator %s", op)
+		assert.Equal(t, "[Label!]", listComparator.Fields.ForName(op).Type.String())
+	}
+	assert.Len(t, schema.Types["LabelComparator"].Fields, 1, "hand-written comparators should not be replaced")
+}
+
 // Test_initInputs tests the filter input initialization
 func Test_initInputs(t *testing.T) {
 	tests := []struct {
@@ -902,8 +950,13 @@ func buildTestSchema(t *testing.T, schemaSDL string) *ast.Schema {
 			BuiltIn: false,
 		},
 	}
+	scalars, err := scalarsSource(cfg.Sources)
+	require.NoError(t, err)
+	if scalars != nil {
+		cfg.Sources = append(cfg.Sources, scalars)
+	}
 
-	err := cfg.LoadSchema()
+	err = cfg.LoadSchema()
 	require.NoError(t, err, "Failed to load test schema")
 
 	return cfg.Schema
@@ -15,6 +15,11 @@ import (
 // Generate generates the schema and the resolver files, if generateServer is true, it will also generate the server file.
 // if saveFiles is true, it will save the generated augmented graphql files to the disk, otherwise it the only be saved in generated code.
 func Generate(configPath string, generateServer, saveFiles bool, sources ...*ast.Source) error {
+	return GenerateWithOptions(configPath, generateServer, saveFiles, nil, sources...)
+}
+
+// GenerateWithOptions is Generate with the FastGqlPlugin configured by opts, i.e. WithAggregates
+func GenerateWithOptions(configPath string, generateServer, saveFiles bool, opts []Option, sources ...*ast.Source) error {
 	log.Printf("loading config from %s", configPath)
 	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
@@ -23,11 +28,23 @@ func Generate(configPath string, generateServer, saveFiles bool, sources ...*ast
 	if sources != nil {
 		cfg.Sources = append(cfg.Sources, sources...)
 	}
+	fedSources, err := federationSources(cfg)
+	if err != nil {
+		return err
+	}
+	cfg.Sources = append(cfg.Sources, fedSources...)
+	scalars, err := scalarsSource(cfg.Sources)
+	if err != nil {
+		return err
+	}
+	if scalars != nil {
+		cfg.Sources = append(cfg.Sources, scalars)
+	}
 	if err := cfg.LoadSchema(); err != nil {
 		return err
 	}
 	// initialize the FastGQL plugin and add it to gqlgen
-	fgqlPlugin := NewFastGQLPlugin(cfg.Resolver.Package, "server.go", generateServer)
+	fgqlPlugin := NewFastGQLPlugin(cfg.Resolver.Package, "server.go", generateServer, opts...)
 	srcs, err := fgqlPlugin.CreateAugmented(cfg.Schema)
 	if err != nil {
 		return err
@@ -45,6 +62,10 @@ func Generate(configPath string, generateServer, saveFiles bool, sources ...*ast
 			return err
 		}
 	}
+	// the fastgql scalars are builtin and aren't part of the augmented sources, they are added again after saving
+	if scalars != nil {
+		cfg.Sources = append(cfg.Sources, scalars)
+	}
 	// Attaching the mutation function onto modelgen plugin
 	p := modelgen.Plugin{
 		MutateHook: mutateHook,
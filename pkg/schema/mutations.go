@@ -3,8 +3,11 @@ package schema
 import (
 	"fmt"
 	"log"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/iancoleman/strcase"
 	"github.com/jinzhu/inflection"
 	"github.com/spf13/cast"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -33,9 +36,20 @@ func MutationsAugmenter(s *ast.Schema) error {
 		}
 		log.Printf("adding mutations for %s", v.Name)
 		def := s.Types[v.Name]
+		if def.Kind == ast.Interface && (def.Directives.ForName(tableDirectiveName) == nil || def.Directives.ForName("typename") == nil) {
+			return fmt.Errorf("mutations on interface %s require @table and @typename directives", def.Name)
+		}
+		if td, err := GetTableDirective(def); err == nil && td.View {
+			return fmt.Errorf("mutations can't be generated for %s, its table %s is a read-only view", def.Name, td.Name)
+		}
 		args := d.ArgumentMap(nil)
 		if c, ok := args["create"]; ok && cast.ToBool(c) {
-			createFieldDef := addCreateMutation(s, def)
+			var createFieldDef *ast.FieldDefinition
+			if def.Kind == ast.Interface {
+				createFieldDef = addInterfaceCreateMutation(s, def)
+			} else {
+				createFieldDef = addCreateMutation(s, def)
+			}
 			s.Mutation.Fields = append(s.Mutation.Fields, createFieldDef)
 		}
 		if c, ok := args["delete"]; ok && cast.ToBool(c) {
@@ -113,10 +127,20 @@ func addCreateMutation(s *ast.Schema, obj *ast.Definition) *ast.FieldDefinition
 	}
 	s.Types[fmt.Sprintf("create%s", inflection.Plural(obj.Name))] = inputObject
 	for _, f := range obj.Fields {
-		if strings.HasPrefix(f.Name, "__") {
+		// computed fields are derived from the row and can't be written
+		if strings.HasPrefix(f.Name, "__") || GetComputedDirective(f) != nil {
 			continue
 		}
 		fieldDef := s.Types[f.Type.Name()]
+		// typed JSON fields are written as a whole to their column
+		if GetJSONDirective(f) != nil && fieldDef.IsCompositeType() {
+			inputObject.Fields = append(inputObject.Fields, &ast.FieldDefinition{
+				Name:        f.Name,
+				Description: f.Description,
+				Type:        jsonInputType(s, f.Type),
+			})
+			continue
+		}
 		// We don't support composite types
 		if fieldDef.IsCompositeType() {
 			continue
@@ -153,6 +177,79 @@ func addCreateMutation(s *ast.Schema, obj *ast.Definition) *ast.FieldDefinition
 	}
 }
 
+// addInterfaceCreateMutation adds a create mutation for an interface stored in a single discriminated table,
+// each input row holds exactly one of the interface implementations, and the typename column is set automatically.
+func addInterfaceCreateMutation(s *ast.Schema, obj *ast.Definition) *ast.FieldDefinition {
+	inputObject := &ast.Definition{
+		Kind:        ast.InputObject,
+		Name:        fmt.Sprintf("Create%sInput", obj.Name),
+		Description: fmt.Sprintf("AutoGenerated input for %s, exactly one implementation must be set", obj.Name),
+	}
+	s.Types[inputObject.Name] = inputObject
+	typeNameField := getTypeNameField(obj)
+	// copy the possible types before sorting, the slice is shared with the schema
+	implementations := slices.Clone(s.GetPossibleTypes(obj))
+	sort.Slice(implementations, func(i, j int) bool {
+		return implementations[i].Name < implementations[j].Name
+	})
+	for _, impl := range implementations {
+		implInput := &ast.Definition{
+			Kind:        ast.InputObject,
+			Name:        fmt.Sprintf("Create%s%sInput", obj.Name, impl.Name),
+			Description: fmt.Sprintf("AutoGenerated input for %s implementation of %s", impl.Name, obj.Name),
+		}
+		s.Types[implInput.Name] = implInput
+		for _, f := range impl.Fields {
+			if strings.HasPrefix(f.Name, "__") || f.Name == typeNameField || GetComputedDirective(f) != nil {
+				continue
+			}
+			// We don't support composite types
+			if s.Types[f.Type.Name()].IsCompositeType() {
+				continue
+			}
+			implInput.Fields = append(implInput.Fields, &ast.FieldDefinition{
+				Name:        f.Name,
+				Description: f.Description,
+				Type:        f.Type,
+			})
+		}
+		inputObject.Fields = append(inputObject.Fields, &ast.FieldDefinition{
+			Name:        strcase.ToLowerCamel(impl.Name),
+			Description: fmt.Sprintf("Create a %s", impl.Name),
+			Type:        &ast.Type{NamedType: implInput.Name},
+		})
+	}
+
+	return &ast.FieldDefinition{
+		Description: fmt.Sprintf("AutoGenerated input for %s", obj.Name),
+		Name:        fmt.Sprintf("create%s", inflection.Plural(obj.Name)),
+		Arguments: []*ast.ArgumentDefinition{
+			{
+				Name: "inputs",
+				Type: &ast.Type{
+					Elem: &ast.Type{
+						NamedType: inputObject.Name,
+						NonNull:   true,
+					},
+					NonNull: true,
+				},
+			},
+		},
+		Type: &ast.Type{
+			NamedType: getPayloadObject(s, obj).Name,
+		},
+	}
+}
+
+// getTypeNameField returns the field name holding the interface's @typename, empty if there is none
+func getTypeNameField(obj *ast.Definition) string {
+	d := obj.Directives.ForName("typename")
+	if d == nil {
+		return ""
+	}
+	return cast.ToString(GetDirectiveValue(d, "name"))
+}
+
 func addUpdateMutation(s *ast.Schema, obj *ast.Definition) *ast.FieldDefinition {
 	inputObject := &ast.Definition{
 		Kind:        ast.InputObject,
@@ -160,11 +257,26 @@ func addUpdateMutation(s *ast.Schema, obj *ast.Definition) *ast.FieldDefinition
 		Description: fmt.Sprintf("AutoGenerated update input for %s", obj.Name),
 	}
 	s.Types[fmt.Sprintf("update%s", inflection.Plural(obj.Name))] = inputObject
+	typeNameField := getTypeNameField(obj)
 	for _, f := range obj.Fields {
-		if strings.HasPrefix(f.Name, "__") {
+		// the type of interface rows can't be changed, and computed fields can't be written
+		if strings.HasPrefix(f.Name, "__") || f.Name == typeNameField || GetComputedDirective(f) != nil {
 			continue
 		}
 		fieldDef := s.Types[f.Type.Name()]
+		// typed JSON objects are replaced or deep merged, lists of JSON objects are replaced
+		if GetJSONDirective(f) != nil && fieldDef.IsCompositeType() {
+			fieldType := jsonInputType(s, f.Type)
+			if !IsListType(f.Type) {
+				fieldType = &ast.Type{NamedType: addJSONUpdateInput(s, fieldDef).Name}
+			}
+			inputObject.Fields = append(inputObject.Fields, &ast.FieldDefinition{
+				Name:        f.Name,
+				Description: f.Description,
+				Type:        fieldType,
+			})
+			continue
+		}
 		// We don't support composite types
 		if fieldDef.IsCompositeType() {
 			continue
@@ -230,6 +342,75 @@ func addUpdateMutation(s *ast.Schema, obj *ast.Definition) *ast.FieldDefinition
 	return updateDef
 }
 
+// jsonInputType returns the nullable input type of a typed JSON field type, object types are replaced with their input
+func jsonInputType(s *ast.Schema, t *ast.Type) *ast.Type {
+	if t.Elem != nil {
+		return &ast.Type{Elem: jsonInputType(s, t.Elem)}
+	}
+	def := s.Types[t.NamedType]
+	if def == nil || !def.IsCompositeType() {
+		return &ast.Type{NamedType: t.NamedType}
+	}
+	return &ast.Type{NamedType: addJSONInput(s, def).Name}
+}
+
+// addJSONInput adds the input of a JSON object type, all of its fields are optional so it can also be used to merge
+// only the given keys of a JSON value
+func addJSONInput(s *ast.Schema, obj *ast.Definition) *ast.Definition {
+	name := fmt.Sprintf("_%sJsonInput", obj.Name)
+	if def, ok := s.Types[name]; ok {
+		return def
+	}
+	input := &ast.Definition{
+		Kind:        ast.InputObject,
+		Name:        name,
+		Description: fmt.Sprintf("AutoGenerated input for JSON type %s", obj.Name),
+	}
+	// added before its fields to stop recursive JSON types
+	s.Types[name] = input
+	for _, f := range obj.Fields {
+		// unions have no input representation
+		if def := s.Types[f.Type.Name()]; strings.HasPrefix(f.Name, "__") || def == nil || def.Kind == ast.Union {
+			continue
+		}
+		input.Fields = append(input.Fields, &ast.FieldDefinition{
+			Name:        f.Name,
+			Description: f.Description,
+			Type:        jsonInputType(s, f.Type),
+		})
+	}
+	return input
+}
+
+// addJSONUpdateInput adds the update input of a JSON object type, set replaces the JSON value and merge deep merges
+// only the given keys into the current value
+func addJSONUpdateInput(s *ast.Schema, obj *ast.Definition) *ast.Definition {
+	name := fmt.Sprintf("_%sJsonUpdate", obj.Name)
+	if def, ok := s.Types[name]; ok {
+		return def
+	}
+	input := addJSONInput(s, obj)
+	update := &ast.Definition{
+		Kind:        ast.InputObject,
+		Name:        name,
+		Description: fmt.Sprintf("AutoGenerated update input for JSON type %s, either set or merge must be given", obj.Name),
+		Fields: []*ast.FieldDefinition{
+			{
+				Name:        "set",
+				Description: "Replace the JSON value",
+				Type:        &ast.Type{NamedType: input.Name},
+			},
+			{
+				Name:        "merge",
+				Description: "Deep merge the given keys into the JSON value",
+				Type:        &ast.Type{NamedType: input.Name},
+			},
+		},
+	}
+	s.Types[name] = update
+	return update
+}
+
 func getPayloadObject(s *ast.Schema, obj *ast.Definition) *ast.Definition {
 	payloadObjectName := fmt.Sprintf("%sPayload", inflection.Plural(obj.Name))
 	if payloadObject, ok := s.Types[payloadObjectName]; ok {
@@ -290,6 +290,55 @@ func Test_addUpdateMutation(t *testing.T) {
 	}
 }
 
+// Test_addMutation_Json tests typed @json fields are written through nested input types
+func Test_addMutation_Json(t *testing.T) {
+	schema := buildTestSchema(t, `
+		type Product {
+			id: ID!
+			attributes: Attributes @json(column: "attributes")
+			reviews: [Review] @json(column: "reviews")
+		}
+		type Attributes {
+			color: String!
+			details: Details
+		}
+		type Details {
+			model: String
+		}
+		type Review {
+			rating: Int!
+		}
+		input AttributesInput {
+			unrelated: Int
+		}
+	`)
+	objDef := schema.Types["Product"]
+	require.NotNil(t, addCreateMutation(schema, objDef))
+	require.NotNil(t, addUpdateMutation(schema, objDef))
+
+	createInput := schema.Types["createProducts"]
+	require.NotNil(t, createInput)
+	assert.Equal(t, "_AttributesJsonInput", createInput.Fields.ForName("attributes").Type.Name())
+	assert.Equal(t, "[_ReviewJsonInput]", createInput.Fields.ForName("reviews").Type.String())
+
+	attributesInput := schema.Types["_AttributesJsonInput"]
+	require.NotNil(t, attributesInput)
+	assert.Equal(t, ast.InputObject, attributesInput.Kind)
+	assert.False(t, attributesInput.Fields.ForName("color").Type.NonNull, "JSON input fields should be nullable")
+	assert.Equal(t, "_DetailsJsonInput", attributesInput.Fields.ForName("details").Type.Name())
+	assert.Len(t, schema.Types["AttributesInput"].Fields, 1, "user defined inputs aren't reused for JSON writes")
+
+	updateInput := schema.Types["updateProducts"]
+	require.NotNil(t, updateInput)
+	assert.Equal(t, "_AttributesJsonUpdate", updateInput.Fields.ForName("attributes").Type.Name())
+	assert.Equal(t, "[_ReviewJsonInput]", updateInput.Fields.ForName("reviews").Type.String())
+
+	jsonUpdate := schema.Types["_AttributesJsonUpdate"]
+	require.NotNil(t, jsonUpdate)
+	assert.Equal(t, "_AttributesJsonInput", jsonUpdate.Fields.ForName("set").Type.Name())
+	assert.Equal(t, "_AttributesJsonInput", jsonUpdate.Fields.ForName("merge").Type.Name())
+}
+
 // Test_addDeleteMutation tests delete mutation generation
 func Test_addDeleteMutation(t *testing.T) {
 	tests := []struct {
@@ -468,3 +517,87 @@ func Test_schemaHasMutationDirective(t *testing.T) {
 		})
 	}
 }
+
+// Test_addInterfaceCreateMutation tests create mutation generation for interfaces
+func Test_addInterfaceCreateMutation(t *testing.T) {
+	schema := buildTestSchema(t, `
+		interface Animal @table(name: "animals") @typename(name: "type") @generateMutations {
+			id: Int!
+			name: String!
+			type: String!
+		}
+		type Dog implements Animal {
+			id: Int!
+			name: String!
+			type: String!
+			breed: String!
+		}
+		type Cat implements Animal {
+			id: Int!
+			name: String!
+			type: String!
+			color: String
+			owner: Dog
+		}
+	`)
+	result := addInterfaceCreateMutation(schema, schema.Types["Animal"])
+	assert.Equal(t, "createAnimals", result.Name)
+	inputs := result.Arguments.ForName("inputs")
+	require.NotNil(t, inputs)
+	assert.Equal(t, "CreateAnimalInput", inputs.Type.Elem.Name())
+	assert.Equal(t, "AnimalsPayload", result.Type.Name())
+
+	input := schema.Types["CreateAnimalInput"]
+	require.NotNil(t, input)
+	require.Len(t, input.Fields, 2)
+	assert.Equal(t, "cat", input.Fields[0].Name)
+	assert.Equal(t, "CreateAnimalCatInput", input.Fields[0].Type.Name())
+	assert.Equal(t, "dog", input.Fields[1].Name)
+	assert.Equal(t, "CreateAnimalDogInput", input.Fields[1].Type.Name())
+	// the possible types of the schema keep their declaration order
+	possibleTypes := schema.GetPossibleTypes(schema.Types["Animal"])
+	require.Len(t, possibleTypes, 2)
+	assert.Equal(t, "Dog", possibleTypes[0].Name)
+
+	catInput := schema.Types["CreateAnimalCatInput"]
+	require.NotNil(t, catInput)
+	fieldNames := make([]string, 0, len(catInput.Fields))
+	for _, f := range catInput.Fields {
+		fieldNames = append(fieldNames, f.Name)
+	}
+	// typename is set automatically and composite fields aren't supported
+	assert.Equal(t, []string{"id", "name", "color"}, fieldNames)
+}
+
+func Test_MutationsAugmenter_Interface(t *testing.T) {
+	t.Run("discriminated_interface", func(t *testing.T) {
+		schema := buildTestSchema(t, `
+			interface Animal @table(name: "animals") @typename(name: "type") @generateMutations {
+				id: Int!
+				type: String!
+			}
+			type Dog implements Animal {
+				id: Int!
+				type: String!
+			}
+		`)
+		require.NoError(t, MutationsAugmenter(schema))
+		assert.NotNil(t, schema.Mutation.Fields.ForName("createAnimals"))
+		assert.NotNil(t, schema.Mutation.Fields.ForName("deleteAnimals"))
+		update := schema.Mutation.Fields.ForName("updateAnimals")
+		require.NotNil(t, update)
+		assert.Nil(t, schema.Types["updateAnimals"].Fields.ForName("type"), "typename can't be updated")
+	})
+
+	t.Run("missing_typename", func(t *testing.T) {
+		schema := buildTestSchema(t, `
+			interface Animal @table(name: "animals") @generateMutations {
+				id: Int!
+			}
+			type Dog implements Animal {
+				id: Int!
+			}
+		`)
+		require.Error(t, MutationsAugmenter(schema))
+	})
+}
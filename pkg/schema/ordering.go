@@ -3,6 +3,8 @@ package schema
 import (
 	"fmt"
 	"log"
+	"slices"
+	"sort"
 
 	"github.com/spf13/cast"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -69,14 +71,47 @@ func buildOrderingEnum(s *ast.Schema, obj *ast.Definition) *ast.Definition {
 	log.Printf("adding ordering for %s\n", obj.Name)
 	for _, f := range obj.Fields {
 		fieldDef := s.Types[f.Type.Name()]
-		// Ordering only supports first level ordering
-		if !fieldDef.IsLeafType() {
+		// typed JSON fields are ordered by their nested fields
+		if GetJSONDirective(f) != nil && !IsListType(f.Type) && fieldDef.IsCompositeType() {
+			if jsonOrdering := buildJSONOrderingInput(s, fieldDef); jsonOrdering != nil {
+				log.Printf("adding json order field %s for %s\n", f.Name, obj.Name)
+				orderInputDef.Fields = append(orderInputDef.Fields, &ast.FieldDefinition{
+					Description: fmt.Sprintf("Order %s by fields of %s", obj.Name, f.Name),
+					Name:        f.Name,
+					Type:        &ast.Type{NamedType: jsonOrdering.Name},
+				})
+			}
 			continue
 		}
-		log.Printf("adding order field %s for %s\n", f.Name, obj.Name)
+		if orderField := buildOrderField(s, obj, f); orderField != nil {
+			log.Printf("adding order field %s for %s\n", f.Name, obj.Name)
+			orderInputDef.Fields = append(orderInputDef.Fields, orderField)
+		}
+	}
+	// abstract types stored in a table per member are ordered by the fields of their members as well, members that
+	// don't have the field are ordered as NULL
+	if isTablePerType(obj) {
+		members := slices.Clone(s.GetPossibleTypes(obj))
+		sort.Slice(members, func(i, j int) bool {
+			return members[i].Name < members[j].Name
+		})
+		for _, member := range members {
+			for _, f := range member.Fields {
+				if orderInputDef.Fields.ForName(f.Name) != nil {
+					continue
+				}
+				if orderField := buildOrderField(s, obj, f); orderField != nil {
+					log.Printf("adding order field %s of %s for %s\n", f.Name, member.Name, obj.Name)
+					orderField.Description = fmt.Sprintf("Order %s by %s of %s", obj.Name, f.Name, member.Name)
+					orderInputDef.Fields = append(orderInputDef.Fields, orderField)
+				}
+			}
+		}
+	}
+	if GetSearchableDirective(obj) != nil {
 		orderInputDef.Fields = append(orderInputDef.Fields, &ast.FieldDefinition{
-			Description: fmt.Sprintf("Order %s by %s", obj.Name, f.Name),
-			Name:        f.Name,
+			Description: fmt.Sprintf("Order %s by full-text search rank", obj.Name),
+			Name:        SearchRankOrderingName,
 			Type:        &ast.Type{NamedType: "_OrderingTypes"},
 		})
 	}
@@ -87,3 +122,76 @@ func buildOrderingEnum(s *ast.Schema, obj *ast.Definition) *ast.Definition {
 	s.Types[orderInputDef.Name] = orderInputDef
 	return orderInputDef
 }
+
+// buildOrderField returns the ordering input field of a leaf field of obj, nil if the field isn't orderable.
+// Ordering only supports first level ordering.
+func buildOrderField(s *ast.Schema, obj *ast.Definition, f *ast.FieldDefinition) *ast.FieldDefinition {
+	fieldDef := s.Types[f.Type.Name()]
+	if fieldDef == nil || !fieldDef.IsLeafType() || !isOrderable(s, fieldDef) {
+		return nil
+	}
+	orderType := "_OrderingTypes"
+	if IsGeoType(fieldDef.Name) {
+		orderType = DistanceOrderingName
+	}
+	return &ast.FieldDefinition{
+		Description: fmt.Sprintf("Order %s by %s", obj.Name, f.Name),
+		Name:        f.Name,
+		Type:        &ast.Type{NamedType: orderType},
+	}
+}
+
+// isTablePerType returns true if each possible type of the abstract type is stored in its own table: unions without
+// a @table directive, or interfaces without a @typename discriminator column.
+func isTablePerType(def *ast.Definition) bool {
+	switch def.Kind {
+	case ast.Union:
+		return def.Directives.ForName(tableDirectiveName) == nil
+	case ast.Interface:
+		return def.Directives.ForName("typename") == nil
+	}
+	return false
+}
+
+// buildJSONOrderingInput builds the ordering input of a JSON type, ordering by its leaf fields and the fields of its
+// nested objects, i.e. {details: {price: DESC}}. It's named apart from the <Type>Ordering of table types, as a type
+// can be both a table and stored in a JSON column.
+func buildJSONOrderingInput(s *ast.Schema, obj *ast.Definition) *ast.Definition {
+	name := fmt.Sprintf("_%sJsonOrdering", obj.Name)
+	if def, ok := s.Types[name]; ok {
+		return def
+	}
+	orderInputDef := &ast.Definition{
+		Kind:        ast.InputObject,
+		Description: fmt.Sprintf("Ordering for %s", obj.Name),
+		Name:        name,
+	}
+	// added before its fields to stop recursive JSON types
+	s.Types[name] = orderInputDef
+	for _, f := range obj.Fields {
+		fieldDef := s.Types[f.Type.Name()]
+		if IsListType(f.Type) || fieldDef == nil {
+			continue
+		}
+		orderType := "_OrderingTypes"
+		if fieldDef.IsCompositeType() {
+			nested := buildJSONOrderingInput(s, fieldDef)
+			if nested == nil {
+				continue
+			}
+			orderType = nested.Name
+		} else if !fieldDef.IsLeafType() || !isOrderable(s, fieldDef) {
+			continue
+		}
+		orderInputDef.Fields = append(orderInputDef.Fields, &ast.FieldDefinition{
+			Description: fmt.Sprintf("Order %s by %s", obj.Name, f.Name),
+			Name:        f.Name,
+			Type:        &ast.Type{NamedType: orderType},
+		})
+	}
+	if len(orderInputDef.Fields) == 0 {
+		delete(s.Types, name)
+		return nil
+	}
+	return orderInputDef
+}
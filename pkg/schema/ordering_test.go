@@ -220,6 +220,78 @@ func Test_buildOrderingEnum(t *testing.T) {
 	}
 }
 
+func Test_buildOrderingEnum_Geo(t *testing.T) {
+	schema := buildTestSchema(t, `
+		type Place {
+			id: ID!
+			location: Geometry
+			position: Geography
+		}
+	`)
+	result := buildOrderingEnum(schema, schema.Types["Place"])
+	require.NotNil(t, result)
+	assert.Equal(t, "_OrderingTypes", result.Fields.ForName("id").Type.Name())
+	assert.Equal(t, "_DistanceOrdering", result.Fields.ForName("location").Type.Name())
+	assert.Equal(t, "_DistanceOrdering", result.Fields.ForName("position").Type.Name())
+}
+
+func Test_buildOrderingEnum_Json(t *testing.T) {
+	schema := buildTestSchema(t, `
+		type Product {
+			id: ID!
+			attributes: Attributes @json(column: "attributes")
+			reviews: [Review] @json(column: "reviews")
+		}
+		type Attributes {
+			price: Float
+			tags: [String]
+			details: Details
+			parent: Attributes
+		}
+		type Details {
+			manufacturer: String
+		}
+		type Review {
+			rating: Int
+		}
+	`)
+	result := buildOrderingEnum(schema, schema.Types["Product"])
+	require.NotNil(t, result)
+	assert.Equal(t, "_AttributesJsonOrdering", result.Fields.ForName("attributes").Type.Name())
+	assert.Nil(t, result.Fields.ForName("reviews"))
+
+	attributes := schema.Types["_AttributesJsonOrdering"]
+	require.NotNil(t, attributes)
+	assert.Equal(t, "_OrderingTypes", attributes.Fields.ForName("price").Type.Name())
+	assert.Equal(t, "_DetailsJsonOrdering", attributes.Fields.ForName("details").Type.Name())
+	assert.Equal(t, "_AttributesJsonOrdering", attributes.Fields.ForName("parent").Type.Name())
+	assert.Nil(t, attributes.Fields.ForName("tags"))
+	assert.Equal(t, "_OrderingTypes", schema.Types["_DetailsJsonOrdering"].Fields.ForName("manufacturer").Type.Name())
+}
+
+// Test_buildOrderingEnum_JsonTable tests a type that is both a table and stored in a JSON column keeps its ordering
+func Test_buildOrderingEnum_JsonTable(t *testing.T) {
+	schema := buildTestSchema(t, `
+		type Product @table(name: "products") {
+			id: ID!
+			name: String
+			supplier: Supplier @json(column: "supplier")
+		}
+		type Supplier @table(name: "suppliers") {
+			id: ID!
+			name: String
+			products: [Product] @relation(type: ONE_TO_MANY, fields: ["id"], references: ["supplier_id"])
+		}
+	`)
+	supplierOrdering := buildOrderingEnum(schema, schema.Types["Supplier"])
+	require.NotNil(t, supplierOrdering)
+	productOrdering := buildOrderingEnum(schema, schema.Types["Product"])
+	require.NotNil(t, productOrdering)
+	assert.Equal(t, "_SupplierJsonOrdering", productOrdering.Fields.ForName("supplier").Type.Name())
+	assert.Same(t, supplierOrdering, schema.Types["SupplierOrdering"], "the table ordering isn't replaced")
+	assert.NotNil(t, schema.Types["_SupplierJsonOrdering"].Fields.ForName("name"))
+}
+
 // Test_addOrderByArgsToField tests adding orderBy arguments to fields
 func Test_addOrderByArgsToField(t *testing.T) {
 	tests := []struct {
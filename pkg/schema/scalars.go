@@ -0,0 +1,147 @@
+package schema
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/spf13/cast"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Scalar describes how fields of a custom scalar are filtered, ordered and aggregated, i.e. UUID or Decimal
+type Scalar struct {
+	// Name of the scalar in the schema
+	Name string
+	// Operators are the operators of the generated <Name>Comparator input, i.e. eq, in and gt. If empty, no comparator
+	// is generated and a <Name>Comparator defined in the schema is used
+	Operators []string
+	// Orderable allows ordering by fields of the scalar and computing their max and min
+	Orderable bool
+	// Aggregatable allows computing the sum and avg of fields of the scalar
+	Aggregatable bool
+	// AggregateType is the result type of sum and avg, defaults to Float
+	AggregateType string
+	// Cast is the SQL type filter values are cast to, i.e. uuid or numeric
+	Cast string
+}
+
+// NewScalarsAugmenter returns an Augmenter declaring how fields of the given custom scalars are filtered, ordered and
+// aggregated by adding their @scalar directive to the scalars declared in the schema. A @scalar directive already in the
+// schema takes precedence.
+func NewScalarsAugmenter(scalars ...Scalar) Augmenter {
+	return func(s *ast.Schema) error {
+		for _, sc := range scalars {
+			def := s.Types[sc.Name]
+			if def == nil || def.Kind != ast.Scalar || def.Directives.ForName(scalarDirectiveName) != nil {
+				continue
+			}
+			log.Printf("adding @scalar directive to %s\n", sc.Name)
+			def.Directives = append(def.Directives, scalarDirective(s, sc))
+		}
+		return nil
+	}
+}
+
+// scalarDirective returns the @scalar directive declaring the scalar
+func scalarDirective(s *ast.Schema, sc Scalar) *ast.Directive {
+	boolValue := func(b bool) *ast.Value {
+		return &ast.Value{Kind: ast.BooleanValue, Raw: strconv.FormatBool(b)}
+	}
+	args := ast.ArgumentList{
+		{Name: "orderable", Value: boolValue(sc.Orderable)},
+		{Name: "aggregatable", Value: boolValue(sc.Aggregatable)},
+	}
+	if len(sc.Operators) > 0 {
+		operators := &ast.Value{Kind: ast.ListValue}
+		for _, op := range sc.Operators {
+			operators.Children = append(operators.Children, &ast.ChildValue{Value: &ast.Value{Kind: ast.StringValue, Raw: op}})
+		}
+		args = append(args, &ast.Argument{Name: "operators", Value: operators})
+	}
+	if sc.AggregateType != "" {
+		args = append(args, &ast.Argument{Name: "aggregateType", Value: &ast.Value{Kind: ast.StringValue, Raw: sc.AggregateType}})
+	}
+	if sc.Cast != "" {
+		args = append(args, &ast.Argument{Name: "cast", Value: &ast.Value{Kind: ast.StringValue, Raw: sc.Cast}})
+	}
+	return &ast.Directive{
+		Name:       scalarDirectiveName,
+		Arguments:  args,
+		Definition: s.Directives[scalarDirectiveName],
+	}
+}
+
+// GetScalar returns the scalar declared with the @scalar directive in the schema
+func GetScalar(s *ast.Schema, name string) (Scalar, bool) {
+	if s == nil {
+		return Scalar{}, false
+	}
+	def := s.Types[name]
+	if def == nil || def.Kind != ast.Scalar {
+		return Scalar{}, false
+	}
+	d := def.Directives.ForName(scalarDirectiveName)
+	if d == nil {
+		return Scalar{}, false
+	}
+	return Scalar{
+		Name:          name,
+		Operators:     cast.ToStringSlice(GetDirectiveValue(d, "operators")),
+		Orderable:     cast.ToBool(GetDirectiveValue(d, "orderable")),
+		Aggregatable:  cast.ToBool(GetDirectiveValue(d, "aggregatable")),
+		AggregateType: cast.ToString(GetDirectiveValue(d, "aggregateType")),
+		Cast:          cast.ToString(GetDirectiveValue(d, "cast")),
+	}, true
+}
+
+// isOrderable returns true if fields of the leaf type can be ordered, scalars are orderable unless they're declared
+// not orderable
+func isOrderable(s *ast.Schema, def *ast.Definition) bool {
+	if sc, ok := GetScalar(s, def.Name); ok {
+		return sc.Orderable
+	}
+	return true
+}
+
+// addScalarComparators adds the <Scalar>Comparator inputs of the declared scalars that have operators, unless the
+// comparator is already defined in the schema
+func addScalarComparators(s *ast.Schema) {
+	for _, def := range s.Types {
+		if def.Kind != ast.Scalar {
+			continue
+		}
+		sc, ok := GetScalar(s, def.Name)
+		if !ok || len(sc.Operators) == 0 {
+			continue
+		}
+		comparatorName := fmt.Sprintf("%sComparator", def.Name)
+		if _, exists := s.Types[comparatorName]; exists {
+			continue
+		}
+		log.Printf("adding comparator %s for scalar %s\n", comparatorName, def.Name)
+		comparator := &ast.Definition{
+			Kind:        ast.InputObject,
+			Description: fmt.Sprintf("Comparator for %s", def.Name),
+			Name:        comparatorName,
+		}
+		for _, op := range sc.Operators {
+			comparator.Fields = append(comparator.Fields, &ast.FieldDefinition{
+				Name: op,
+				Type: scalarOperatorType(def.Name, op),
+			})
+		}
+		s.Types[comparatorName] = comparator
+	}
+}
+
+// scalarOperatorType returns the type of the operator's value in a scalar comparator
+func scalarOperatorType(scalar, op string) *ast.Type {
+	switch op {
+	case "in", "notIn":
+		return &ast.Type{Elem: &ast.Type{NamedType: scalar, NonNull: true}}
+	case "isNull":
+		return &ast.Type{NamedType: "Boolean"}
+	}
+	return &ast.Type{NamedType: scalar}
+}
@@ -0,0 +1,145 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const scalarsTestSchema = `
+	scalar UUID @scalar(operators: ["eq", "in", "isNull"], cast: "uuid")
+	scalar Decimal @scalar(operators: ["eq", "gt"], orderable: true, aggregatable: true, aggregateType: "Decimal", cast: "numeric")
+	scalar Money
+	type Account {
+		id: UUID!
+		balance: Decimal
+		credit: Money
+	}
+`
+
+func TestGetScalar(t *testing.T) {
+	s := buildTestSchema(t, scalarsTestSchema)
+
+	sc, ok := GetScalar(s, "Decimal")
+	require.True(t, ok)
+	assert.Equal(t, Scalar{
+		Name:          "Decimal",
+		Operators:     []string{"eq", "gt"},
+		Orderable:     true,
+		Aggregatable:  true,
+		AggregateType: "Decimal",
+		Cast:          "numeric",
+	}, sc)
+
+	_, ok = GetScalar(s, "Money")
+	assert.False(t, ok, "scalars without the @scalar directive aren't declared")
+
+	_, ok = GetScalar(s, "String")
+	assert.False(t, ok)
+}
+
+func TestNewScalarsAugmenter(t *testing.T) {
+	s := buildTestSchema(t, scalarsTestSchema)
+	money := Scalar{
+		Name:          "Money",
+		Operators:     []string{"eq", "in"},
+		Orderable:     true,
+		Aggregatable:  true,
+		AggregateType: "Float",
+		Cast:          "money",
+	}
+	augmenter := NewScalarsAugmenter(money, Scalar{Name: "Decimal", Cast: "float8"}, Scalar{Name: "Undeclared", Cast: "text"})
+	require.NoError(t, augmenter(s))
+
+	// the declared scalars are kept in the formatted schema
+	formatted := &ast.Schema{Types: map[string]*ast.Definition{}}
+	for _, src := range Format("", s) {
+		doc, err := parser.ParseSchema(src)
+		require.NoError(t, err)
+		for _, def := range doc.Definitions {
+			formatted.Types[def.Name] = def
+		}
+	}
+	s = formatted
+	sc, ok := GetScalar(s, "Money")
+	require.True(t, ok)
+	assert.Equal(t, money, sc)
+
+	sc, ok = GetScalar(s, "Decimal")
+	require.True(t, ok)
+	assert.Equal(t, "numeric", sc.Cast, "the @scalar directive in the schema takes precedence")
+
+	assert.Nil(t, s.Types["Undeclared"], "scalars that aren't declared in the schema are ignored")
+}
+
+func Test_addScalarComparators(t *testing.T) {
+	s := buildTestSchema(t, scalarsTestSchema)
+	addScalarComparators(s)
+
+	uuidComparator := s.Types["UUIDComparator"]
+	require.NotNil(t, uuidComparator)
+	assert.Equal(t, ast.InputObject, uuidComparator.Kind)
+	require.Len(t, uuidComparator.Fields, 3)
+	assert.Equal(t, "UUID", uuidComparator.Fields.ForName("eq").Type.String())
+	assert.Equal(t, "[UUID!]", uuidComparator.Fields.ForName("in").Type.String())
+	assert.Equal(t, "Boolean", uuidComparator.Fields.ForName("isNull").Type.String())
+
+	require.NotNil(t, s.Types["DecimalComparator"])
+	assert.Nil(t, s.Types["MoneyComparator"], "scalars without operators should not get a comparator")
+}
+
+func Test_addScalarComparators_Existing(t *testing.T) {
+	s := buildTestSchema(t, scalarsTestSchema+`
+	input UUIDComparator {
+		eq: UUID
+	}
+	`)
+	addScalarComparators(s)
+	assert.Len(t, s.Types["UUIDComparator"].Fields, 1, "comparators defined in the schema should not be replaced")
+}
+
+func Test_Scalars_OrderingAndAggregates(t *testing.T) {
+	s := buildTestSchema(t, scalarsTestSchema)
+	obj := s.Types["Account"]
+
+	ordering := buildOrderingEnum(s, obj)
+	require.NotNil(t, ordering)
+	assert.Nil(t, ordering.Fields.ForName("id"), "UUID is not orderable")
+	assert.NotNil(t, ordering.Fields.ForName("balance"))
+	assert.NotNil(t, ordering.Fields.ForName("credit"), "undeclared scalars are orderable")
+
+	tests := []struct {
+		aggregate string
+		field     string
+		wantType  string
+	}{
+		{aggregate: "max", field: "balance", wantType: "Decimal!"},
+		{aggregate: "sum", field: "balance", wantType: "Decimal!"},
+		{aggregate: "avg", field: "balance", wantType: "Decimal!"},
+		{aggregate: "max", field: "id"},
+		{aggregate: "sum", field: "id"},
+		{aggregate: "sum", field: "credit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.aggregate+"_"+tt.field, func(t *testing.T) {
+			var a Aggregate
+			for _, at := range defaultAggregates {
+				if at.Name == tt.aggregate {
+					a = at
+				}
+			}
+			f := addAggregationFieldToSchema(s, obj, a)
+			require.NotNil(t, f)
+			aggField := s.Types[f.Type.Name()].Fields.ForName(tt.field)
+			if tt.wantType == "" {
+				assert.Nil(t, aggField)
+				return
+			}
+			require.NotNil(t, aggField)
+			assert.Equal(t, tt.wantType, aggField.Type.String())
+		})
+	}
+}
@@ -2,6 +2,10 @@ package schema
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
 
 	"github.com/spf13/cast"
 	"github.com/vektah/gqlparser/v2/ast"
@@ -13,6 +17,16 @@ const (
 	GroupBy     ArgName = "groupBy"
 	FilterInput ArgName = "filter"
 	OrderBy     ArgName = "orderBy"
+	Search      ArgName = "search"
+)
+
+const (
+	// SearchFilterFieldName is the filter input comparator of @searchable types
+	SearchFilterFieldName = "_search"
+	// SearchRankOrderingName is the ordering input field of @searchable types, ordering by the full-text search rank
+	SearchRankOrderingName = "_rank"
+	// defaultSearchConfig is the text search configuration used if @searchable has no config
+	defaultSearchConfig = "english"
 )
 
 const (
@@ -21,8 +35,247 @@ const (
 	tableDirectiveName        = "table"
 	relationDirectiveName     = "relation"
 	jsonDirectiveName         = "json"
+	fastgqlFieldDirectiveName = "fastgqlField"
+	searchableDirectiveName   = "searchable"
+	scalarDirectiveName       = "scalar"
+	computedDirectiveName     = "computed"
+	functionDirectiveName     = "function"
+	sqlMutationDirectiveName  = "sqlMutation"
+	primaryDirectiveName      = "primary"
+	streamDirectiveName       = "stream"
 )
 
+const (
+	// GeometryScalarName is the PostGIS geometry scalar, values are GeoJSON objects
+	GeometryScalarName = "Geometry"
+	// GeographyScalarName is the PostGIS geography scalar, values are GeoJSON objects in WGS 84 and distances are in meters
+	GeographyScalarName = "Geography"
+	// DistanceOrderingName is the ordering input of Geometry and Geography fields, ordering by distance from a point
+	DistanceOrderingName = "_DistanceOrdering"
+)
+
+const (
+	// DateScalarName is a calendar date scalar i.e. 2006-01-02
+	DateScalarName = "Date"
+	// DateTimeScalarName is an RFC 3339 timestamp scalar
+	DateTimeScalarName = "DateTime"
+	// TimeOfDayScalarName is a time of day scalar i.e. 15:04:05, gqlgen's builtin Time scalar is left a timestamp
+	TimeOfDayScalarName = "TimeOfDay"
+	// IntervalScalarName is a Postgres interval scalar i.e. "3 days"
+	IntervalScalarName = "Interval"
+)
+
+// TimeBuckets are the date_trunc precisions date and timestamp fields can be grouped by in aggregates
+var TimeBuckets = []string{"hour", "day", "week", "month"}
+
+// IsTemporalType returns true if the type name is the Date or DateTime scalar
+func IsTemporalType(name string) bool {
+	return name == DateScalarName || name == DateTimeScalarName
+}
+
+// timeBucketGroupByName returns the group by enum value of a field truncated to the bucket, i.e. CREATED_AT_DAY
+func timeBucketGroupByName(field, bucket string) string {
+	return strcase.ToScreamingSnake(field) + "_" + strings.ToUpper(bucket)
+}
+
+// timeBucketsOf returns the time buckets a field of the given type can be grouped by, dates have no hour bucket
+func timeBucketsOf(typeName string) []string {
+	switch typeName {
+	case DateTimeScalarName:
+		return TimeBuckets
+	case DateScalarName:
+		return TimeBuckets[1:]
+	}
+	return nil
+}
+
+// GetTimeBucket returns the field and date_trunc precision of a time bucket group by value, i.e. CREATED_AT_DAY
+// groups by date_trunc('day', created_at). If the value isn't a time bucket of the definition ok is false.
+func GetTimeBucket(def *ast.Definition, groupBy string) (field *ast.FieldDefinition, bucket string, ok bool) {
+	for _, f := range def.Fields {
+		if IsListType(f.Type) {
+			continue
+		}
+		for _, b := range timeBucketsOf(f.Type.Name()) {
+			if timeBucketGroupByName(f.Name, b) == groupBy {
+				return f, b, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// GetComputedGroupBy returns the @computed field of the group by value, i.e. FULL_NAME of fullName
+func GetComputedGroupBy(def *ast.Definition, groupBy string) (*ast.FieldDefinition, bool) {
+	for _, f := range def.Fields {
+		if GetComputedDirective(f) != nil && !IsListType(f.Type) && strcase.ToScreamingSnake(f.Name) == groupBy {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// RelationGroupBy is a group by value pointing into a field of a ONE_TO_ONE relation
+type RelationGroupBy struct {
+	// Relation is the ONE_TO_ONE relation field of the grouped type
+	Relation *ast.FieldDefinition
+	// Field is the leaf field of the related type that is grouped by
+	Field *ast.FieldDefinition
+}
+
+// Name returns the group by enum value, i.e. USER__NAME groups by the name of the user relation
+func (r RelationGroupBy) Name() string {
+	return strcase.ToScreamingSnake(r.Relation.Name) + "__" + strcase.ToScreamingSnake(r.Field.Name)
+}
+
+// relationGroupBys returns the group by values of the leaf fields of the ONE_TO_ONE relations of the definition
+func relationGroupBys(s *ast.Schema, def *ast.Definition) []RelationGroupBy {
+	var groupBys []RelationGroupBy
+	for _, rf := range def.Fields {
+		rel := GetRelationDirective(rf)
+		if rel == nil || rel.RelType != OneToOne || IsListType(rf.Type) {
+			continue
+		}
+		relDef := s.Types[rf.Type.Name()]
+		if relDef == nil {
+			continue
+		}
+		for _, f := range relDef.Fields {
+			if fd := s.Types[f.Type.Name()]; IsListType(f.Type) || fd == nil || !fd.IsLeafType() {
+				continue
+			}
+			groupBys = append(groupBys, RelationGroupBy{Relation: rf, Field: f})
+		}
+	}
+	return groupBys
+}
+
+// GetRelationGroupBy returns the relation group by of a group by value, i.e. USER__NAME. If the value doesn't
+// point into a ONE_TO_ONE relation of the definition ok is false.
+func GetRelationGroupBy(s *ast.Schema, def *ast.Definition, groupBy string) (RelationGroupBy, bool) {
+	for _, r := range relationGroupBys(s, def) {
+		if r.Name() == groupBy {
+			return r, true
+		}
+	}
+	return RelationGroupBy{}, false
+}
+
+// JSONPath is a path to a leaf field nested in a typed JSON field, used to order and group by JSON fields
+type JSONPath struct {
+	// JSON is the @json field of the type
+	JSON *ast.FieldDefinition
+	// Path is the fields leading from the JSON field's type to the leaf field, i.e. details then manufacturer
+	Path []*ast.FieldDefinition
+}
+
+// Name returns the group by enum value, i.e. ATTRIBUTES__DETAILS__MANUFACTURER groups by attributes.details.manufacturer
+func (j JSONPath) Name() string {
+	name := strcase.ToScreamingSnake(j.JSON.Name)
+	for _, f := range j.Path {
+		name += "__" + strcase.ToScreamingSnake(f.Name)
+	}
+	return name
+}
+
+// Column returns the column of the JSON field
+func (j JSONPath) Column() string {
+	return GetJSONDirective(j.JSON).Column
+}
+
+// Keys returns the JSON object keys of the path, i.e. [details manufacturer]
+func (j JSONPath) Keys() []string {
+	keys := make([]string, 0, len(j.Path))
+	for _, f := range j.Path {
+		keys = append(keys, f.Name)
+	}
+	return keys
+}
+
+// Leaf returns the leaf field the path points to
+func (j JSONPath) Leaf() *ast.FieldDefinition {
+	return j.Path[len(j.Path)-1]
+}
+
+// jsonGroupBys returns the group by values of the leaf fields nested in the typed JSON fields of the definition
+func jsonGroupBys(s *ast.Schema, def *ast.Definition) []JSONPath {
+	var groupBys []JSONPath
+	for _, jf := range def.Fields {
+		if GetJSONDirective(jf) == nil || IsListType(jf.Type) {
+			continue
+		}
+		jsonDef := s.Types[jf.Type.Name()]
+		if jsonDef == nil || !jsonDef.IsCompositeType() {
+			continue
+		}
+		for _, path := range jsonLeafPaths(s, jsonDef) {
+			groupBys = append(groupBys, JSONPath{JSON: jf, Path: path})
+		}
+	}
+	return groupBys
+}
+
+// jsonLeafPaths returns the paths to the leaf fields nested in a JSON type, lists and recursive types are skipped
+func jsonLeafPaths(s *ast.Schema, def *ast.Definition, visited ...*ast.Definition) [][]*ast.FieldDefinition {
+	var paths [][]*ast.FieldDefinition
+	visited = append(visited, def)
+	for _, f := range def.Fields {
+		fd := s.Types[f.Type.Name()]
+		if IsListType(f.Type) || fd == nil {
+			continue
+		}
+		if fd.IsLeafType() {
+			paths = append(paths, []*ast.FieldDefinition{f})
+			continue
+		}
+		if !fd.IsCompositeType() || hasVisited(fd, visited) {
+			continue
+		}
+		for _, p := range jsonLeafPaths(s, fd, visited...) {
+			paths = append(paths, append([]*ast.FieldDefinition{f}, p...))
+		}
+	}
+	return paths
+}
+
+// GetJSONGroupBy returns the JSON path of a group by value, i.e. ATTRIBUTES__COLOR. If the value doesn't point into
+// a typed JSON field of the definition ok is false.
+func GetJSONGroupBy(s *ast.Schema, def *ast.Definition, groupBy string) (JSONPath, bool) {
+	for _, j := range jsonGroupBys(s, def) {
+		if j.Name() == groupBy {
+			return j, true
+		}
+	}
+	return JSONPath{}, false
+}
+
+// GetJSONPath returns the path of keys into the typed JSON field of the definition, i.e. attributes and
+// [details manufacturer]. If the field isn't a typed JSON field or the keys don't exist ok is false.
+func GetJSONPath(s *ast.Schema, def *ast.Definition, jsonField string, keys []string) (JSONPath, bool) {
+	jf := def.Fields.ForName(jsonField)
+	if jf == nil || GetJSONDirective(jf) == nil || len(keys) == 0 {
+		return JSONPath{}, false
+	}
+	j := JSONPath{JSON: jf}
+	f := jf
+	for _, k := range keys {
+		fd := s.Types[f.Type.Name()]
+		if fd == nil {
+			return JSONPath{}, false
+		}
+		if f = fd.Fields.ForName(k); f == nil {
+			return JSONPath{}, false
+		}
+		j.Path = append(j.Path, f)
+	}
+	return j, true
+}
+
+// IsGeoType returns true if the type name is the Geometry or Geography scalar
+func IsGeoType(name string) bool {
+	return name == GeometryScalarName || name == GeographyScalarName
+}
+
 type TableDirective struct {
 	// Name of the table/collection
 	Name string
@@ -30,6 +283,24 @@ type TableDirective struct {
 	Schema string
 	// Dialect name the table resides in
 	Dialect string
+	// View marks the table as a read-only view, mutations can't be generated for it
+	View bool
+	// Timeout overrides the default statement timeout of the table's queries, zero if it isn't set
+	Timeout time.Duration
+}
+
+// FunctionDirective is a root field backed by a Postgres set-returning function, called with the field's arguments
+type FunctionDirective struct {
+	// Name of the function
+	Name string
+	// Schema name the function resides in, can be omitted
+	Schema string
+}
+
+// generatedArguments are the names of the arguments added by the augmenters, they aren't passed to @function functions
+var generatedArguments = map[string]bool{
+	string(FilterInput): true, string(OrderBy): true, string(GroupBy): true, string(Search): true,
+	"limit": true, "offset": true, "having": true,
 }
 
 type RelationType string
@@ -55,18 +326,105 @@ type JSONDirective struct {
 	Column string
 }
 
+// ComputedDirective is a field derived from the row, selected as an SQL expression or by calling a function with the row
+type ComputedDirective struct {
+	// SQL is the expression of the field, i.e. first_name || ' ' || last_name
+	SQL string
+	// Function is called with the row to compute the field, i.e. app.full_name
+	Function string
+}
+
+type FastgqlFieldDirective struct {
+	// SkipSelect if true, the field isn't selected by builders and is expected to be resolved manually
+	SkipSelect bool
+	// Keys are the parent columns the field depends on, they are selected even if they weren't requested
+	Keys []string
+}
+
+type SearchableDirective struct {
+	// Columns are the fields included in the full-text search document
+	Columns []string
+	// Config is the text search configuration, i.e. english
+	Config string
+}
+
+// GetTableDirective returns the @table directive of the type, a timeout that isn't a valid duration is ignored, it's
+// validated when the schema is generated by TableAugmenter
 func GetTableDirective(def *ast.Definition) (*TableDirective, error) {
 	d := def.Directives.ForName("table")
 	if d == nil {
 		return nil, fmt.Errorf("failed to get table directive for %s", def.Name)
 	}
+	timeout, _ := time.ParseDuration(getArgumentValue(d.Arguments, "timeout"))
 	return &TableDirective{
 		Name:    getArgumentValue(d.Arguments, "name"),
 		Schema:  getArgumentValue(d.Arguments, "schema"),
 		Dialect: getArgumentValue(d.Arguments, "dialect"),
+		View:    cast.ToBool(GetDirectiveValue(d, "view")),
+		Timeout: timeout,
 	}, nil
 }
 
+// TableAugmenter validates the @table directives of the schema, failing the generation if a timeout isn't a valid
+// duration
+func TableAugmenter(s *ast.Schema) error {
+	for _, def := range s.Types {
+		d := def.Directives.ForName("table")
+		if d == nil {
+			continue
+		}
+		if v := getArgumentValue(d.Arguments, "timeout"); v != "" {
+			if _, err := time.ParseDuration(v); err != nil {
+				return fmt.Errorf("invalid timeout of table directive for %s: %w", def.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetFunctionDirective returns the @function directive of the field, if it exists.
+func GetFunctionDirective(field *ast.FieldDefinition) *FunctionDirective {
+	d := field.Directives.ForName(functionDirectiveName)
+	if d == nil {
+		return nil
+	}
+	return &FunctionDirective{
+		Name:   cast.ToString(GetDirectiveValue(d, "name")),
+		Schema: cast.ToString(GetDirectiveValue(d, "schema")),
+	}
+}
+
+// GetSQLMutationDirective returns the function of a @sqlMutation field, if it exists. The function is called with the
+// field's arguments, its returned rows are the rows of the field's type.
+func GetSQLMutationDirective(field *ast.FieldDefinition) *FunctionDirective {
+	d := field.Directives.ForName(sqlMutationDirectiveName)
+	if d == nil {
+		return nil
+	}
+	return &FunctionDirective{
+		Name:   cast.ToString(GetDirectiveValue(d, "function")),
+		Schema: cast.ToString(GetDirectiveValue(d, "schema")),
+	}
+}
+
+// IsPrimaryField returns true if the field has a @primary directive, its queries are always sent to the primary
+// database instead of a read replica
+func IsPrimaryField(field *ast.FieldDefinition) bool {
+	return field.Directives.ForName(primaryDirectiveName) != nil
+}
+
+// FunctionArguments returns the arguments of a @function or @sqlMutation field passed to the function, in order,
+// which are the field's arguments that weren't added by the augmenters
+func FunctionArguments(field *ast.FieldDefinition) ast.ArgumentDefinitionList {
+	var args ast.ArgumentDefinitionList
+	for _, a := range field.Arguments {
+		if !generatedArguments[a.Name] {
+			args = append(args, a)
+		}
+	}
+	return args
+}
+
 func GetRelationDirective(field *ast.FieldDefinition) *RelationDirective {
 	d := field.Directives.ForName(relationDirectiveName)
 	if d == nil {
@@ -95,6 +453,58 @@ func GetJSONDirective(field *ast.FieldDefinition) *JSONDirective {
 	}
 }
 
+// GetComputedDirective returns the @computed directive of the field, if it exists.
+func GetComputedDirective(field *ast.FieldDefinition) *ComputedDirective {
+	d := field.Directives.ForName(computedDirectiveName)
+	if d == nil {
+		return nil
+	}
+	return &ComputedDirective{
+		SQL:      cast.ToString(GetDirectiveValue(d, "sql")),
+		Function: cast.ToString(GetDirectiveValue(d, "function")),
+	}
+}
+
+// GetFastgqlFieldDirective returns the @fastgqlField directive of the field, if it exists.
+// If no keys are given, the relation fields are used as keys if the field has a @relation directive.
+func GetFastgqlFieldDirective(field *ast.FieldDefinition) *FastgqlFieldDirective {
+	d := field.Directives.ForName(fastgqlFieldDirectiveName)
+	if d == nil {
+		return nil
+	}
+	skipSelect := true
+	if v := GetDirectiveValue(d, "skipSelect"); v != nil {
+		skipSelect = cast.ToBool(v)
+	}
+	keys := cast.ToStringSlice(GetDirectiveValue(d, "keys"))
+	if rel := GetRelationDirective(field); len(keys) == 0 && rel != nil {
+		keys = rel.Fields
+	}
+	return &FastgqlFieldDirective{
+		SkipSelect: skipSelect,
+		Keys:       keys,
+	}
+}
+
+// GetSearchableDirective returns the @searchable directive of the definition, if it exists
+func GetSearchableDirective(def *ast.Definition) *SearchableDirective {
+	if def == nil {
+		return nil
+	}
+	d := def.Directives.ForName(searchableDirectiveName)
+	if d == nil {
+		return nil
+	}
+	config := cast.ToString(GetDirectiveValue(d, "config"))
+	if config == "" {
+		config = defaultSearchConfig
+	}
+	return &SearchableDirective{
+		Columns: cast.ToStringSlice(GetDirectiveValue(d, "columns")),
+		Config:  config,
+	}
+}
+
 func getArgumentValue(args ast.ArgumentList, name string) string {
 	arg := args.ForName(name)
 	if arg == nil {
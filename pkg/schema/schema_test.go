@@ -2,6 +2,7 @@ package schema
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -62,6 +63,47 @@ func Test_GetTableDirective(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "parses_table_directive_with_view",
+			schemaDefinition: `
+				type ProductStats @table(name: "product_stats", view: true) {
+					id: ID!
+				}
+			`,
+			typeName: "ProductStats",
+			expectedTable: &TableDirective{
+				Name: "product_stats",
+				View: true,
+			},
+			expectError: false,
+		},
+		{
+			name: "parses_table_directive_with_timeout",
+			schemaDefinition: `
+				type Report @table(name: "reports", timeout: "5s") {
+					id: ID!
+				}
+			`,
+			typeName: "Report",
+			expectedTable: &TableDirective{
+				Name:    "reports",
+				Timeout: 5 * time.Second,
+			},
+			expectError: false,
+		},
+		{
+			name: "ignores_invalid_timeout",
+			schemaDefinition: `
+				type Report @table(name: "reports", timeout: "5 seconds") {
+					id: ID!
+				}
+			`,
+			typeName: "Report",
+			expectedTable: &TableDirective{
+				Name: "reports",
+			},
+			expectError: false,
+		},
 		{
 			name: "returns_error_when_no_directive",
 			schemaDefinition: `
@@ -107,11 +149,28 @@ func Test_GetTableDirective(t *testing.T) {
 				assert.Equal(t, tt.expectedTable.Name, result.Name)
 				assert.Equal(t, tt.expectedTable.Schema, result.Schema)
 				assert.Equal(t, tt.expectedTable.Dialect, result.Dialect)
+				assert.Equal(t, tt.expectedTable.Timeout, result.Timeout)
 			}
 		})
 	}
 }
 
+func TestTableAugmenter(t *testing.T) {
+	s := buildTestSchema(t, `
+		type Report @table(name: "reports", timeout: "30s") {
+			id: ID!
+		}
+	`)
+	require.NoError(t, TableAugmenter(s))
+
+	s = buildTestSchema(t, `
+		type Report @table(name: "reports", timeout: "5 seconds") {
+			id: ID!
+		}
+	`)
+	assert.ErrorContains(t, TableAugmenter(s), "invalid timeout of table directive for Report")
+}
+
 // Test_GetRelationDirective tests relation directive parsing
 func Test_GetRelationDirective(t *testing.T) {
 	tests := []struct {
@@ -320,3 +379,94 @@ func Test_getArgumentValue(t *testing.T) {
 	}
 }
 
+
+// Test_GetTimeBucket tests time bucket group by value parsing
+func Test_GetTimeBucket(t *testing.T) {
+	schema := buildTestSchema(t, `
+		type Event {
+			id: ID!
+			createdAt: DateTime!
+			day: Date
+			startsAt: TimeOfDay
+		}
+	`)
+	def := schema.Types["Event"]
+	require.NotNil(t, def)
+	tests := []struct {
+		groupBy        string
+		expectedField  string
+		expectedBucket string
+		expectOk       bool
+	}{
+		{groupBy: "CREATED_AT_HOUR", expectedField: "createdAt", expectedBucket: "hour", expectOk: true},
+		{groupBy: "CREATED_AT_MONTH", expectedField: "createdAt", expectedBucket: "month", expectOk: true},
+		{groupBy: "DAY_WEEK", expectedField: "day", expectedBucket: "week", expectOk: true},
+		{groupBy: "DAY_HOUR", expectOk: false},
+		{groupBy: "STARTS_AT_DAY", expectOk: false},
+		{groupBy: "CREATED_AT", expectOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			f, bucket, ok := GetTimeBucket(def, tt.groupBy)
+			require.Equal(t, tt.expectOk, ok)
+			if !tt.expectOk {
+				return
+			}
+			assert.Equal(t, tt.expectedField, f.Name)
+			assert.Equal(t, tt.expectedBucket, bucket)
+		})
+	}
+}
+
+func Test_GetJSONGroupBy(t *testing.T) {
+	schema := buildTestSchema(t, `
+		type Product {
+			id: ID!
+			attributes: Attributes @json(column: "attrs")
+			reviews: [Review] @json(column: "reviews")
+		}
+		type Attributes {
+			color: String
+			tags: [String]
+			details: Details
+		}
+		type Details {
+			year: Int
+		}
+		type Review {
+			rating: Int
+		}
+	`)
+	def := schema.Types["Product"]
+	require.NotNil(t, def)
+	tests := []struct {
+		groupBy      string
+		expectedKeys []string
+		expectOk     bool
+	}{
+		{groupBy: "ATTRIBUTES__COLOR", expectedKeys: []string{"color"}, expectOk: true},
+		{groupBy: "ATTRIBUTES__DETAILS__YEAR", expectedKeys: []string{"details", "year"}, expectOk: true},
+		{groupBy: "ATTRIBUTES__TAGS", expectOk: false},
+		{groupBy: "REVIEWS__RATING", expectOk: false},
+		{groupBy: "ID", expectOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			j, ok := GetJSONGroupBy(schema, def, tt.groupBy)
+			require.Equal(t, tt.expectOk, ok)
+			if !tt.expectOk {
+				return
+			}
+			assert.Equal(t, tt.expectedKeys, j.Keys())
+			assert.Equal(t, "attrs", j.Column())
+		})
+	}
+
+	j, ok := GetJSONPath(schema, def, "attributes", []string{"details", "year"})
+	require.True(t, ok)
+	assert.Equal(t, "Int", j.Leaf().Type.Name())
+	_, ok = GetJSONPath(schema, def, "attributes", []string{"details", "missing"})
+	assert.False(t, ok)
+	_, ok = GetJSONPath(schema, def, "id", []string{"details"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cast"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// SearchAugmenter adds a search argument to generated list fields whose type has a @searchable directive
+func SearchAugmenter(s *ast.Schema) error {
+	for _, v := range s.Query.Fields {
+		d := v.Directives.ForName(generateDirectiveName)
+		if d == nil {
+			continue
+		}
+		if !IsListType(v.Type) {
+			continue
+		}
+		if err := addSearchArgToField(s, s.Query, v); err != nil {
+			return err
+		}
+		args := d.ArgumentMap(nil)
+		if recursive := cast.ToBool(args["recursive"]); recursive {
+			if err := addRecursive(s, s.Types[GetType(v.Type).Name()], string(Search), addSearchArgToField); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func addSearchArgToField(s *ast.Schema, obj *ast.Definition, field *ast.FieldDefinition) error {
+	if skipAugment(field, string(Search)) {
+		return nil
+	}
+	def := s.Types[GetType(field.Type).Name()]
+	if GetSearchableDirective(def) == nil {
+		return nil
+	}
+	log.Printf("adding search argument to field %s@%s\n", field.Name, obj.Name)
+	field.Arguments = append(field.Arguments, &ast.ArgumentDefinition{
+		Description: fmt.Sprintf("Full-text search %s", field.Name),
+		Name:        string(Search),
+		Type:        &ast.Type{NamedType: "String"},
+	})
+	return nil
+}
@@ -0,0 +1,121 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SearchAugmenter(t *testing.T) {
+	tests := []struct {
+		name         string
+		schema       string
+		queryField   string
+		expectSearch bool
+	}{
+		{
+			name: "adds_search_to_searchable_list_field",
+			schema: `
+				type Post @searchable(columns: ["title", "body"]) {
+					id: Int!
+					title: String
+					body: String
+				}
+				type Query {
+					posts: [Post] @generate
+				}
+			`,
+			queryField:   "posts",
+			expectSearch: true,
+		},
+		{
+			name: "skips_non_searchable_type",
+			schema: `
+				type Post {
+					id: Int!
+				}
+				type Query {
+					posts: [Post] @generate
+				}
+			`,
+			queryField:   "posts",
+			expectSearch: false,
+		},
+		{
+			name: "skips_non_list_fields",
+			schema: `
+				type Post @searchable(columns: ["title"]) {
+					id: Int!
+					title: String
+				}
+				type Query {
+					post: Post @generate
+				}
+			`,
+			queryField:   "post",
+			expectSearch: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := buildTestSchema(t, tt.schema)
+			require.NoError(t, SearchAugmenter(s))
+			arg := s.Query.Fields.ForName(tt.queryField).Arguments.ForName(string(Search))
+			if tt.expectSearch {
+				require.NotNil(t, arg)
+				assert.Equal(t, "String", arg.Type.Name())
+			} else {
+				assert.Nil(t, arg)
+			}
+		})
+	}
+}
+
+func Test_SearchableInputs(t *testing.T) {
+	s := buildTestSchema(t, `
+		type Post @searchable(columns: ["title", "body"]) @generateFilterInput {
+			id: Int!
+			title: String
+			body: String
+		}
+		type Query {
+			posts: [Post] @generate
+		}
+	`)
+	require.NoError(t, OrderByAugmenter(s))
+	require.NoError(t, FilterInputAugmenter(s))
+
+	rank := s.Types["PostOrdering"].Fields.ForName(SearchRankOrderingName)
+	require.NotNil(t, rank)
+	assert.Equal(t, "_OrderingTypes", rank.Type.Name())
+	search := s.Types["PostFilterInput"].Fields.ForName(SearchFilterFieldName)
+	require.NotNil(t, search)
+	assert.Equal(t, "String", search.Type.Name())
+}
+
+func Test_GetSearchableDirective(t *testing.T) {
+	s := buildTestSchema(t, `
+		type Post @searchable(columns: ["title", "body"]) {
+			id: Int!
+			title: String
+			body: String
+		}
+		type Comment @searchable(columns: ["body"], config: "simple") {
+			id: Int!
+			body: String
+		}
+		type Query {
+			posts: [Post]
+			comments: [Comment]
+		}
+	`)
+	d := GetSearchableDirective(s.Types["Post"])
+	require.NotNil(t, d)
+	assert.Equal(t, []string{"title", "body"}, d.Columns)
+	assert.Equal(t, "english", d.Config)
+	d = GetSearchableDirective(s.Types["Comment"])
+	require.NotNil(t, d)
+	assert.Equal(t, "simple", d.Config)
+	assert.Nil(t, GetSearchableDirective(s.Types["Query"]))
+}